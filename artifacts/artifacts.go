@@ -0,0 +1,12 @@
+// Package artifacts embeds the manifests the controller can install itself
+package artifacts
+
+import (
+	// embed is needed for go:embed
+	_ "embed"
+)
+
+// CRDs is the manifest of the Route and Router custom resource definitions
+//
+//go:embed prefixrouter/crd.yaml
+var CRDs []byte
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// newLeaderElector builds a Lease based elector that calls run while
+// this replica holds the lease. Losing the lease terminates the process
+// so that a restarted replica rejoins the election with a clean state.
+func newLeaderElector(
+	kubeClient kubernetes.Interface,
+	run func(stopCh <-chan struct{}),
+	logger *zap.SugaredLogger,
+	stopCh <-chan struct{},
+) (*leaderelection.LeaderElector, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hostname: %v", err)
+	}
+	identity := hostname + "_" + string(uuid.NewUUID())
+
+	lock, err := resourcelock.New(
+		resourcelock.LeasesResourceLock,
+		leaderElectionNamespace,
+		leaderElectionName,
+		kubeClient.CoreV1(),
+		kubeClient.CoordinationV1(),
+		resourcelock.ResourceLockConfig{Identity: identity},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lease lock: %v", err)
+	}
+
+	return leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   leaseDuration,
+		RenewDeadline:   renewDeadline,
+		RetryPeriod:     retryPeriod,
+		ReleaseOnCancel: true,
+		Name:            leaderElectionName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				logger.Infof("Acquired leadership as %s", identity)
+				run(ctx.Done())
+			},
+			OnStoppedLeading: func() {
+				select {
+				case <-stopCh:
+					logger.Infof("Released leadership as %s", identity)
+				default:
+					logger.Fatalf("Lost leadership as %s", identity)
+				}
+			},
+			OnNewLeader: func(leader string) {
+				if leader != identity {
+					logger.Infof("Standing by, current leader is %s", leader)
+				}
+			},
+		},
+	})
+}
+
+func runLeaderElection(elector *leaderelection.LeaderElector, stopCh <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+
+	elector.Run(ctx)
+}
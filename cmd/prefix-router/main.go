@@ -1,17 +1,22 @@
 package main
 
 import (
+	"errors"
 	"flag"
+	"fmt"
 	semver "github.com/Masterminds/semver/v3"
 	consulapi "github.com/hashicorp/consul/api"
 	"github.com/oleksiyp/prefixrouter/controller"
 	clientset "github.com/oleksiyp/prefixrouter/pkg/client/clientset/versioned"
 	"github.com/oleksiyp/prefixrouter/pkg/client/informers/externalversions"
 	"github.com/oleksiyp/prefixrouter/pkg/client/informers/externalversions/prefixrouter/v1beta1"
+	"github.com/oleksiyp/prefixrouter/pkg/consul"
 	"github.com/oleksiyp/prefixrouter/pkg/logger"
+	"github.com/oleksiyp/prefixrouter/pkg/routing"
 	"github.com/oleksiyp/prefixrouter/pkg/signals"
 	"github.com/oleksiyp/prefixrouter/server"
 	"go.uber.org/zap"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -28,7 +33,27 @@ var (
 	zapEncoding string
 	namespace   string
 	serviceName string
+	instance    string
+	backend     string
 	port        string
+
+	webhookPort string
+	tlsCertFile string
+	tlsKeyFile  string
+
+	consulConfigFile  string
+	consulTokenSecret string
+	consulFlags       consul.Config
+
+	startupTimeout time.Duration
+	installCRDs    bool
+
+	leaderElect             bool
+	leaderElectionName      string
+	leaderElectionNamespace string
+	leaseDuration           time.Duration
+	renewDeadline           time.Duration
+	retryPeriod             time.Duration
 )
 
 func init() {
@@ -37,8 +62,33 @@ func init() {
 	flag.StringVar(&logLevel, "log-level", "debug", "Log level can be: debug, info, warning, error.")
 	flag.StringVar(&zapEncoding, "zap-encoding", "json", "Zap logger encoding.")
 	flag.StringVar(&namespace, "namespace", "", "Namespace that prefix router would watch route object.")
-	flag.StringVar(&serviceName, "serviceName", "", "Service name that prefix router will configure.")
+	flag.StringVar(&serviceName, "serviceName", "", "Service name of the router that routes without spec.router are added to.")
+	flag.StringVar(&instance, "instance", "", "Name tagging what this prefix router writes, only tagged routes are cleaned up on startup. Defaults to <namespace>/<serviceName>, has to differ between deployments sharing a Consul cluster.")
+	flag.StringVar(&backend, "backend", consul.BackendName, "Backend applying the routes of routers that don't select one, only consul is available.")
 	flag.StringVar(&port, "port", "8080", "Port to listen on.")
+	flag.StringVar(&consulConfigFile, "consul-config", "", "Path to a YAML or JSON Consul client configuration, flags override its values.")
+	flag.StringVar(&consulFlags.Address, "consul-address", "", "Address of the Consul HTTP API. Defaults to CONSUL_HTTP_ADDR or 127.0.0.1:8500.")
+	flag.StringVar(&consulFlags.Scheme, "consul-scheme", "", "Scheme of the Consul HTTP API, http or https.")
+	flag.StringVar(&consulFlags.CAFile, "consul-ca-file", "", "Path to the CA certificate verifying Consul.")
+	flag.StringVar(&consulFlags.CertFile, "consul-cert-file", "", "Path to the client certificate presented to Consul.")
+	flag.StringVar(&consulFlags.KeyFile, "consul-key-file", "", "Path to the private key of the client certificate.")
+	flag.StringVar(&consulFlags.Token, "consul-token", "", "Consul ACL token.")
+	flag.StringVar(&consulFlags.TokenFile, "consul-token-file", "", "Path to a file with the Consul ACL token, reloaded when it changes.")
+	flag.StringVar(&consulTokenSecret, "consul-token-secret", "", "Secret key holding the Consul ACL token as namespace/name/key, watched for rotation.")
+	flag.StringVar(&consulFlags.Datacenter, "consul-datacenter", "", "Consul datacenter. Defaults to the datacenter of the agent.")
+	flag.StringVar(&consulFlags.Namespace, "consul-namespace", "", "Consul Enterprise namespace of the config entries.")
+	flag.StringVar(&consulFlags.Partition, "consul-partition", "", "Consul Enterprise admin partition of the config entries.")
+	flag.StringVar(&webhookPort, "webhook-port", "9443", "Port the admission and conversion webhooks listen on.")
+	flag.StringVar(&tlsCertFile, "tls-cert-file", "", "Path to the webhook TLS certificate, reloaded when it changes. The webhooks are disabled if empty.")
+	flag.StringVar(&tlsKeyFile, "tls-key-file", "", "Path to the webhook TLS private key, reloaded when it changes.")
+	flag.DurationVar(&startupTimeout, "startup-timeout", 5*time.Minute, "How long to wait for Consul and the CRDs to become available before exiting.")
+	flag.BoolVar(&installCRDs, "install-crds", false, "Create or update the Route and Router CRDs from the manifest embedded in the binary.")
+	flag.BoolVar(&leaderElect, "leader-elect", false, "Elect a leader among replicas, only the leader configures Consul.")
+	flag.StringVar(&leaderElectionName, "leader-election-name", "prefix-router", "Name of the Lease used for leader election.")
+	flag.StringVar(&leaderElectionNamespace, "leader-election-namespace", "", "Namespace of the Lease used for leader election. Defaults to --namespace.")
+	flag.DurationVar(&leaseDuration, "leader-election-lease-duration", 15*time.Second, "Duration standby replicas wait before taking over an expired lease.")
+	flag.DurationVar(&renewDeadline, "leader-election-renew-deadline", 10*time.Second, "Duration the leader retries renewing the lease before giving it up.")
+	flag.DurationVar(&retryPeriod, "leader-election-retry-period", 2*time.Second, "Interval between leader election attempts.")
 }
 
 func main() {
@@ -57,8 +107,20 @@ func main() {
 
 	logger.Infof("Starting prefix router")
 
-	if serviceName == "" {
-		logger.Fatalf("Missing --serviceName parameter")
+	if leaderElectionNamespace == "" {
+		leaderElectionNamespace = namespace
+	}
+	if leaderElect && leaderElectionNamespace == "" {
+		logger.Fatalf("Missing --leader-election-namespace parameter")
+	}
+	if instance == "" {
+		instance = namespace + "/" + serviceName
+	}
+	if backend != consul.BackendName {
+		logger.Fatalf("Unknown backend %s", backend)
+	}
+	if (tlsCertFile == "") != (tlsKeyFile == "") {
+		logger.Fatalf("--tls-cert-file and --tls-key-file have to be set together")
 	}
 
 	cfg, err := clientcmd.BuildConfigFromFlags(masterURL, kubeconfig)
@@ -76,55 +138,158 @@ func main() {
 		logger.Fatalf("Error building prefix router clientset: %v", err)
 	}
 
-	consulClient, err := consulapi.NewClient(consulapi.DefaultConfig())
+	consulConfig := consulFlags
+	if consulConfigFile != "" {
+		fileConfig, err := consul.LoadConfigFile(consulConfigFile)
+		if err != nil {
+			logger.Fatalf("Error loading consul config: %v", err)
+		}
+		consulConfig = fileConfig.Merge(consulFlags)
+	}
+
+	if consulTokenSecret == "" {
+		consulConfig = consulConfig.WithEnvironmentTokenFile()
+	}
+
+	consulToken := &consul.Token{}
+	consulClient, err := consul.NewClient(consulConfig, consulToken)
 	if err != nil {
 		logger.Fatalf("Error building consul client: %v", err)
 	}
+	if consulConfig.TokenFile != "" {
+		go consul.WatchTokenFile(consulConfig.TokenFile, consulToken, 10*time.Second, logger, stopCh)
+	}
+	if consulTokenSecret != "" {
+		if consulConfig.TokenFile != "" {
+			logger.Fatalf("--consul-token-secret and --consul-token-file are mutually exclusive")
+		}
+		secret, err := consul.ParseSecretKey(consulTokenSecret)
+		if err != nil {
+			logger.Fatalf("Error parsing --consul-token-secret: %v", err)
+		}
+		if err := consul.WatchTokenSecret(kubeClient, secret, consulToken, logger, stopCh); err != nil {
+			logger.Fatalf("Error reading consul token: %v", err)
+		}
+	}
+
+	health := server.NewHealth()
+	go server.ListenAndServe(port, 3*time.Second, health, logger, stopCh)
 
-	verifyCRDs(prefixRouterClient, logger)
 	verifyKubernetesVersion(kubeClient, logger)
-	verifyConsulClient(*consulClient, logger)
 
-	routeInformer := startInformers(prefixRouterClient, logger, stopCh)
+	if installCRDs {
+		apiextensionsClient, err := apiextensions.NewForConfig(cfg)
+		if err != nil {
+			logger.Fatalf("Error building apiextensions clientset: %v", err)
+		}
+		if err := installEmbeddedCRDs(apiextensionsClient, logger); err != nil {
+			logger.Fatalf("Error installing CRDs: %v", err)
+		}
+	}
 
-	go server.ListenAndServe(port, 3*time.Second, logger, stopCh)
+	deadline := time.Now().Add(startupTimeout)
+	err = waitForDependency("crds", func() error {
+		return verifyCRDs(prefixRouterClient)
+	}, health, deadline, logger)
+	if err != nil {
+		logger.Fatalf("Error verifying CRDs: %v", err)
+	}
+	err = waitForDependency("consul", func() error {
+		return verifyConsulClient(consulClient, logger)
+	}, health, deadline, logger)
+	if err != nil {
+		logger.Fatalf("Error connecting to Consul: %v", err)
+	}
+
+	health.AddCheck("consulReachable", func() error {
+		_, err := consulClient.Status().Leader()
+		return err
+	})
+
+	routeInformer, routerInformer := startInformers(prefixRouterClient, health, logger, stopCh)
+
+	backends := map[string]routing.Backend{
+		consul.BackendName: consul.NewBackend(consulClient, instance, logger),
+	}
 
 	c := controller.NewController(
 		serviceName,
+		backend,
 		kubeClient,
 		prefixRouterClient,
-		consulClient,
+		backends,
 		routeInformer,
+		routerInformer,
 		logger,
 	)
+	health.AddCheck("consulWrite", c.LastSyncError)
+
+	// every replica answers admission requests, not only the leader
+	if tlsCertFile != "" {
+		go server.ListenAndServeWebhook(webhookPort, tlsCertFile, tlsKeyFile, 3*time.Second, c.AdmitRoute, logger, stopCh)
+	}
 
-	if err := c.Run(stopCh); err != nil {
-		logger.Fatalf("Error running controller: %v", err)
+	run := func(stopCh <-chan struct{}) {
+		if err := c.Run(stopCh); err != nil {
+			logger.Fatalf("Error running controller: %v", err)
+		}
 	}
+
+	if !leaderElect {
+		health.SetLeader(func() bool { return true })
+		run(stopCh)
+		return
+	}
+
+	elector, err := newLeaderElector(kubeClient, run, logger, stopCh)
+	if err != nil {
+		logger.Fatalf("Error setting up leader election: %v", err)
+	}
+
+	health.SetLeader(elector.IsLeader)
+	runLeaderElection(elector, stopCh)
 }
 
 func startInformers(
 	client *clientset.Clientset,
+	health *server.Health,
 	logger *zap.SugaredLogger,
 	stopCh <-chan struct{},
-) v1beta1.RouteInformer {
+) (v1beta1.RouteInformer, v1beta1.RouterInformer) {
 	informerFactory := externalversions.NewSharedInformerFactoryWithOptions(client, time.Second*30, externalversions.WithNamespace(namespace))
 
-	logger.Info("Waiting for route informer cache to sync")
+	logger.Info("Waiting for route and router informer caches to sync")
 	routeInformer := informerFactory.Prefixrouter().V1beta1().Routes()
+	routerInformer := informerFactory.Prefixrouter().V1beta1().Routers()
+	health.AddCheck("informerSynced", func() error {
+		if !routeInformer.Informer().HasSynced() {
+			return errors.New("route informer cache is not synced")
+		}
+		if !routerInformer.Informer().HasSynced() {
+			return errors.New("router informer cache is not synced")
+		}
+		return nil
+	})
 	go routeInformer.Informer().Run(stopCh)
-	if ok := cache.WaitForNamedCacheSync("prefixrouter", stopCh, routeInformer.Informer().HasSynced); !ok {
+	go routerInformer.Informer().Run(stopCh)
+	if ok := cache.WaitForNamedCacheSync("prefixrouter", stopCh, routeInformer.Informer().HasSynced, routerInformer.Informer().HasSynced); !ok {
 		logger.Fatalf("failed to wait for cache to sync")
 	}
 
-	return routeInformer
+	return routeInformer, routerInformer
 }
 
-func verifyCRDs(client clientset.Interface, logger *zap.SugaredLogger) {
+func verifyCRDs(client clientset.Interface) error {
 	_, err := client.PrefixrouterV1beta1().Routes(namespace).List(metav1.ListOptions{Limit: 1})
 	if err != nil {
-		logger.Fatalf("Route CRD is not registered %v", err)
+		return fmt.Errorf("Route CRD is not registered %v", err)
+	}
+
+	_, err = client.PrefixrouterV1beta1().Routers().List(metav1.ListOptions{Limit: 1})
+	if err != nil {
+		return fmt.Errorf("Router CRD is not registered %v", err)
 	}
+	return nil
 }
 
 func verifyKubernetesVersion(kubeClient kubernetes.Interface, logger *zap.SugaredLogger) {
@@ -156,11 +321,12 @@ func verifyKubernetesVersion(kubeClient kubernetes.Interface, logger *zap.Sugare
 	logger.Infof("Connected to Kubernetes API %s", ver)
 }
 
-func verifyConsulClient(client consulapi.Client, logger *zap.SugaredLogger) {
+func verifyConsulClient(client *consulapi.Client, logger *zap.SugaredLogger) error {
 	name, err := client.Agent().NodeName()
 	if err != nil {
-		logger.Fatalf("Consul not reachable %v", err)
+		return fmt.Errorf("Consul not reachable %v", err)
 	}
 
 	logger.Infof("Connected to Consul API, agent node = %s", name)
+	return nil
 }
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/oleksiyp/prefixrouter/artifacts"
+	"github.com/oleksiyp/prefixrouter/server"
+	"go.uber.org/zap"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/yaml"
+)
+
+// waitForDependency retries check with exponential backoff until it
+// succeeds or the deadline passes. Until then /readyz reports the
+// dependency as pending with the last error.
+func waitForDependency(name string, check func() error, health *server.Health, deadline time.Time, logger *zap.SugaredLogger) error {
+	var mu sync.Mutex
+	lastErr := errors.New("not checked yet")
+	health.AddCheck(name, func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		if lastErr != nil {
+			return fmt.Errorf("pending: %v", lastErr)
+		}
+		return nil
+	})
+
+	backoff := wait.Backoff{
+		Duration: time.Second,
+		Factor:   2,
+		Jitter:   0.1,
+		Steps:    int(^uint(0) >> 1),
+		Cap:      30 * time.Second,
+	}
+	for {
+		err := check()
+		mu.Lock()
+		lastErr = err
+		mu.Unlock()
+		if err == nil {
+			return nil
+		}
+
+		delay := backoff.Step()
+		if time.Now().Add(delay).After(deadline) {
+			return fmt.Errorf("%s not available before the startup deadline: %v", name, err)
+		}
+		logger.Warnf("Waiting for %s, retrying in %s: %v", name, delay.Round(time.Second), err)
+		time.Sleep(delay)
+	}
+}
+
+// installEmbeddedCRDs creates or updates the custom resource definitions from
+// the manifest embedded in the binary
+func installEmbeddedCRDs(client apiextensions.Interface, logger *zap.SugaredLogger) error {
+	for _, document := range bytes.Split(artifacts.CRDs, []byte("\n---\n")) {
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := yaml.UnmarshalStrict(document, crd); err != nil {
+			return fmt.Errorf("failed to parse embedded CRD manifest: %v", err)
+		}
+
+		crds := client.ApiextensionsV1().CustomResourceDefinitions()
+		existing, err := crds.Get(crd.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			if _, err := crds.Create(crd); err != nil {
+				return fmt.Errorf("failed to create CRD %s: %v", crd.Name, err)
+			}
+			logger.Infof("Created CRD %s", crd.Name)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get CRD %s: %v", crd.Name, err)
+		}
+
+		keepCABundle(crd, existing)
+		crd.ResourceVersion = existing.ResourceVersion
+		if _, err := crds.Update(crd); err != nil {
+			return fmt.Errorf("failed to update CRD %s: %v", crd.Name, err)
+		}
+		logger.Infof("Updated CRD %s", crd.Name)
+	}
+	return nil
+}
+
+// keepCABundle preserves the conversion webhook CA bundle injected into
+// the installed CRD, as the embedded manifest can't know it
+func keepCABundle(crd, existing *apiextensionsv1.CustomResourceDefinition) {
+	desired := crd.Spec.Conversion
+	current := existing.Spec.Conversion
+	if desired == nil || desired.Webhook == nil || desired.Webhook.ClientConfig == nil ||
+		len(desired.Webhook.ClientConfig.CABundle) > 0 {
+		return
+	}
+	if current == nil || current.Webhook == nil || current.Webhook.ClientConfig == nil {
+		return
+	}
+	desired.Webhook.ClientConfig.CABundle = current.Webhook.ClientConfig.CABundle
+}
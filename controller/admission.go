@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1beta1"
+)
+
+// AdmitRoute tells whether a created or updated route can be applied,
+// catching at admission what the reconcile would otherwise only mark
+// as rejected or conflicting
+func (c Controller) AdmitRoute(route *v1beta1.Route) error {
+	if err := validateRoute(route.Spec); err != nil {
+		return err
+	}
+
+	router := c.routerOf(*route)
+	config, err := c.routerConfig(router)
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		if router == "" {
+			return fmt.Errorf("no router given and no default router configured")
+		}
+		return fmt.Errorf("router %s not found", router)
+	}
+	if config.sharedWith != "" {
+		return fmt.Errorf("router %s is rejected, service %s is already routed by router %s", router, config.serviceName, config.sharedWith)
+	}
+	if err := config.checkPolicy(*route); err != nil {
+		return err
+	}
+
+	routes, err := c.routesOf(router)
+	if err != nil {
+		return err
+	}
+	key := matchKey(route.Spec)
+	for _, other := range routes {
+		if routeKey(*other) == routeKey(*route) {
+			continue
+		}
+		if matchKey(other.Spec) == key {
+			return fmt.Errorf("route %s already takes the same traffic on router %s", routeKey(*other), router)
+		}
+	}
+	return nil
+}
@@ -1,67 +1,91 @@
 package controller
 
 import (
-	consulapi "github.com/hashicorp/consul/api"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1beta1"
 	"github.com/oleksiyp/prefixrouter/pkg/client/clientset/versioned"
 	informer "github.com/oleksiyp/prefixrouter/pkg/client/informers/externalversions/prefixrouter/v1beta1"
+	"github.com/oleksiyp/prefixrouter/pkg/routing"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	// retry delays for failed reconciles, doubled on every failure
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 5 * time.Minute
 )
 
 type Controller struct {
-	serviceName        string
+	defaultRouter      string
+	defaultBackend     string
 	kubeClient         kubernetes.Interface
 	prefixRouterClient *versioned.Clientset
-	consulClient       *consulapi.Client
+	backends           map[string]routing.Backend
 	routeInformer      informer.RouteInformer
+	routerInformer     informer.RouterInformer
 	logger             *zap.SugaredLogger
-	operations         <-chan RouteOperation
-	routes             map[string]string
-}
-
-type RouteOperation struct {
-	Add   bool
-	Route v1beta1.Route
+	recorder           record.EventRecorder
+	queue              workqueue.RateLimitingInterface
+	applied            *routerStates
 }
 
 func (c Controller) Run(stopCh <-chan struct{}) error {
-	for {
-		select {
-		case op := <-c.operations:
-			if op.Add {
-				c.routes[op.Route.Spec.Prefix] = op.Route.Spec.Service
-			} else {
-				delete(c.routes, op.Route.Spec.Prefix)
-			}
-
-			c.refreshRoutes()
-		case <-stopCh:
-			return nil
-		}
+	defer c.queue.ShutDown()
+
+	if err := c.resyncAll(); err != nil {
+		c.logger.Errorf("Failed to clean up routes without a backing object: %v", err)
 	}
+
+	go wait.Until(c.runWorker, time.Second, stopCh)
+	c.watchBackends(stopCh)
+
+	<-stopCh
+	return nil
 }
 
+// NewController creates a controller applying the routing tables of
+// the routers routes refer to through backends, selected by name.
+// Routes without a router go to defaultRouter, routers without a
+// backend use defaultBackend.
 func NewController(
-	serviceName string,
+	defaultRouter string,
+	defaultBackend string,
 	kubeClient kubernetes.Interface,
 	prefixRouterClient *versioned.Clientset,
-	consulClient *consulapi.Client,
+	backends map[string]routing.Backend,
 	routeInformer informer.RouteInformer,
+	routerInformer informer.RouterInformer,
 	logger *zap.SugaredLogger,
 ) *Controller {
-	operations := make(chan RouteOperation)
+	queue := workqueue.NewNamedRateLimitingQueue(
+		workqueue.NewItemExponentialFailureRateLimiter(retryBaseDelay, retryMaxDelay),
+		"routers",
+	)
 
 	controller := &Controller{
-		serviceName,
+		defaultRouter,
+		defaultBackend,
 		kubeClient,
 		prefixRouterClient,
-		consulClient,
+		backends,
 		routeInformer,
+		routerInformer,
 		logger,
-		operations,
-		make(map[string]string),
+		newEventRecorder(kubeClient, logger),
+		queue,
+		newRouterStates(),
 	}
 
 	routeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -71,11 +95,9 @@ func NewController(
 				return
 			}
 
-			logger.Info("Adding route ", route.Spec.Prefix, " -> ", route.Spec.Service)
-			operations <- RouteOperation{
-				Add:   true,
-				Route: route,
-			}
+			logger.Info("Adding route ", routePath(route.Spec), " -> ", routeService(route.Spec))
+			informerEventsTotal.WithLabelValues("add").Inc()
+			controller.enqueue(route)
 		},
 		UpdateFunc: func(oldObj, newObj interface{}) {
 			route, ok := checkCustomResourceType(newObj, logger)
@@ -83,78 +105,434 @@ func NewController(
 				return
 			}
 
-			logger.Info("Updating route ", route.Spec.Prefix, " -> ", route.Spec.Service)
-			operations <- RouteOperation{
-				Add:   true,
-				Route: route,
+			oldRoute, ok := checkCustomResourceType(oldObj, logger)
+			if ok && oldRoute.Generation == route.Generation &&
+				oldRoute.DeletionTimestamp.Equal(route.DeletionTimestamp) &&
+				hasFinalizer(&oldRoute) == hasFinalizer(&route) {
+				// status updates and resyncs don't change the spec
+				return
+			}
+
+			logger.Info("Updating route ", routePath(route.Spec), " -> ", routeService(route.Spec))
+			informerEventsTotal.WithLabelValues("update").Inc()
+			controller.enqueue(route)
+			if ok && controller.routerOf(oldRoute) != controller.routerOf(route) {
+				controller.enqueue(oldRoute)
 			}
 		},
 		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+
 			route, ok := checkCustomResourceType(obj, logger)
 			if !ok {
 				return
 			}
 
-			logger.Info("Deleting route ", route.Spec.Prefix, " -> ", route.Spec.Service)
-			operations <- RouteOperation{
-				Add:   false,
-				Route: route,
+			logger.Info("Deleting route ", routePath(route.Spec), " -> ", routeService(route.Spec))
+			informerEventsTotal.WithLabelValues("delete").Inc()
+			controller.enqueue(route)
+		},
+	})
+
+	routerInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			router, ok := checkRouterType(obj, logger)
+			if !ok {
+				return
 			}
+
+			logger.Info("Adding router ", router.Name)
+			controller.queue.Add(router.Name)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			router, ok := checkRouterType(newObj, logger)
+			if !ok {
+				return
+			}
+
+			oldRouter, ok := checkRouterType(oldObj, logger)
+			if ok && oldRouter.Generation == router.Generation {
+				return
+			}
+
+			logger.Info("Updating router ", router.Name)
+			controller.queue.Add(router.Name)
+			if ok {
+				controller.enqueueSharing(oldRouter)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+
+			router, ok := checkRouterType(obj, logger)
+			if !ok {
+				return
+			}
+
+			logger.Info("Deleting router ", router.Name)
+			controller.queue.Add(router.Name)
+			controller.enqueueSharing(router)
 		},
 	})
 
 	return controller
 }
 
-func (c Controller) refreshRoutes() {
-	configEntry := consulapi.ServiceRouterConfigEntry{
-		Kind:      consulapi.ServiceRouter,
-		Name:      c.serviceName,
-		Namespace: "",
-		Routes:    []consulapi.ServiceRoute{},
-	}
-
-	for prefix, serviceName := range c.routes {
-		configEntry.Routes = append(configEntry.Routes, consulapi.ServiceRoute{
-			Match: &consulapi.ServiceRouteMatch{
-				HTTP: &consulapi.ServiceRouteHTTPMatch{
-					PathPrefix: prefix,
-				},
-			},
-			Destination: &consulapi.ServiceRouteDestination{
-				Service: serviceName,
-			},
-		})
+// LastSyncError returns the error of the last backend write of the first
+// failing router, nil if all succeeded or nothing was written yet.
+// Routers deleted since are skipped.
+func (c Controller) LastSyncError() error {
+	for _, router := range c.applied.routers() {
+		if config, err := c.routerConfig(router); err == nil && config == nil {
+			continue
+		}
+		if err := c.applied.get(router).lastError(); err != nil {
+			return fmt.Errorf("router %s: %v", router, err)
+		}
 	}
+	return nil
+}
 
-	ok, _, err := c.consulClient.ConfigEntries().Set(&configEntry, nil)
+// enqueue schedules a reconcile of the router the route belongs to
+func (c Controller) enqueue(route v1beta1.Route) {
+	c.queue.Add(c.routerOf(route))
+}
+
+// enqueueSharing schedules a reconcile of the routers routing the same
+// service as the router did, one of them may take the service over
+func (c Controller) enqueueSharing(router v1beta1.Router) {
+	serviceName, backend := c.routedService(&router)
+	if serviceName == c.defaultRouter && backend == c.defaultBackend {
+		c.queue.Add(c.defaultRouter)
+	}
 
+	routers, err := c.routerInformer.Lister().List(labels.Everything())
 	if err != nil {
-		c.logger.Errorf("Failed to reconfigure consul: %#v", err)
+		c.logger.Errorf("Failed to list routers: %v", err)
 		return
 	}
+	for _, other := range routers {
+		if otherService, otherBackend := c.routedService(other); otherService == serviceName && otherBackend == backend {
+			c.queue.Add(other.Name)
+		}
+	}
+}
+
+// routerOf returns the name of the router the route is attached to,
+// empty if it names none and there is no default router
+func (c Controller) routerOf(route v1beta1.Route) string {
+	if route.Spec.Router != "" {
+		return route.Spec.Router
+	}
+	return c.defaultRouter
+}
+
+func (c Controller) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+// processNextWorkItem reconciles the next queued router, requeueing it
+// with exponential backoff when the reconcile fails
+func (c Controller) processNextWorkItem() bool {
+	key, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	router := key.(string)
+	if err := c.reconcile(router); err != nil {
+		c.logger.Errorf("Failed to reconcile router %s, retry #%d: %v", router, c.queue.NumRequeues(key), err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+
+	c.queue.Forget(key)
+	return true
+}
+
+// routesOf lists the routes attached to the router
+func (c Controller) routesOf(router string) ([]*v1beta1.Route, error) {
+	all, err := c.routeInformer.Lister().List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list routes: %v", err)
+	}
+
+	var routes []*v1beta1.Route
+	for _, route := range all {
+		if c.routerOf(*route) == router {
+			routes = append(routes, route)
+		}
+	}
+	return routes, nil
+}
+
+// reconcile computes the routing table of the router from its routes
+// and applies it to the backend, reporting the outcome on every route
+func (c Controller) reconcile(router string) error {
+	all, err := c.routesOf(router)
+	if err != nil {
+		return err
+	}
+	all, terminating := splitTerminating(all)
+	if added, err := c.addFinalizers(all); err != nil || added {
+		// the update events of the routes trigger the next reconcile
+		return err
+	}
+
+	config, err := c.routerConfig(router)
+	if err != nil {
+		return err
+	}
+
+	state := c.applied.get(router)
+	switch {
+	case config != nil && config.sharedWith != "" &&
+		state.service == config.serviceName && state.backend == config.backend:
+		// the older router took the service over, its next apply
+		// replaces the routes written for this one
+		c.recordApplied(state, nil)
+		state.set("", 0, nil)
+		managedRoutes.WithLabelValues(router).Set(0)
+		c.queue.Add(config.sharedWith)
+	case config == nil || state.service != config.serviceName || state.backend != config.backend:
+		// the router is gone or points to another service or backend now
+		if err := c.releaseService(router, state); err != nil {
+			return err
+		}
+	}
+
+	if config == nil {
+		unknown := errors.New("router is not set and there is no default router")
+		if router != "" {
+			unknown = fmt.Errorf("router %s does not exist", router)
+		}
+		c.rejectAll(all, unknown)
+		// nothing is applied for the router anymore, including
+		// the error of a write that failed before it was deleted
+		c.applied.remove(router)
+		return c.removeFinalizers(terminating)
+	}
+
+	if config.sharedWith != "" {
+		shared := fmt.Errorf("service %s is already routed by router %s", config.serviceName, config.sharedWith)
+		c.logger.Warnf("Router %s is rejected: %v", router, shared)
+		c.rejectAll(all, shared)
+		c.updateRouterStatus(config, func(status *v1beta1.RouterStatus) {
+			status.AttachedRoutes = 0
+			status.ConflictedRoutes = 0
+			status.RejectedRoutes = int32(len(all))
+			status.Message = fmt.Sprintf("Router is rejected: %v", shared)
+		})
+		return c.removeFinalizers(terminating)
+	}
+
+	backend, ok := c.backends[config.backend]
 	if !ok {
-		c.logger.Errorf("Failed to reconfigure consul: HTTP request returned not 'true'")
-		return
+		unavailable := fmt.Errorf("backend %s of router %s is not available", config.backend, router)
+		c.rejectAll(all, unavailable)
+		c.updateRouterStatus(config, func(status *v1beta1.RouterStatus) {
+			status.Message = unavailable.Error()
+		})
+		return c.removeFinalizers(terminating)
+	}
+
+	timer := prometheus.NewTimer(reconcileDuration.WithLabelValues(router))
+	defer timer.ObserveDuration()
+
+	valid, rejected := validRoutes(all)
+	valid, denied := config.admit(valid)
+	rejected = append(rejected, denied...)
+	for _, rejection := range rejected {
+		rejection := rejection
+		c.logger.Warnf("Route %s is rejected: %v", routeKey(rejection.route), rejection.err)
+		c.recorder.Eventf(&rejection.route, corev1.EventTypeWarning, reasonInvalid, "Route is rejected: %v", rejection.err)
+		c.updateStatus(rejection.route, func(status *v1beta1.RouteStatus) {
+			markInvalid(status, rejection.err)
+		})
 	}
+
+	routes, conflicts := desiredRoutes(valid)
+	for _, conflict := range conflicts {
+		conflict := conflict
+		c.logger.Warnf("Route %s has the same match as %s on path %s, keeping the older one",
+			routeKey(conflict.route), routeKey(conflict.winner), routePath(conflict.route.Spec))
+		c.recorder.Eventf(&conflict.route, corev1.EventTypeWarning, reasonConflicted,
+			"Route %s has the same match and is older, this route is not applied", routeKey(conflict.winner))
+		c.updateStatus(conflict.route, func(status *v1beta1.RouteStatus) {
+			markConflicted(status, conflict.winner)
+		})
+	}
+
+	fallback := config.catchAll()
+	var foreign []routeRejection
+	if checker, ok := backend.(routing.Checker); ok {
+		routes, fallback, foreign, err = c.foreignConflicts(checker, config, routes)
+		if err != nil {
+			err = fmt.Errorf("failed to check %s for conflicts: %v", config.backend, err)
+			state.fail(err)
+			return err
+		}
+	}
+	for _, conflict := range foreign {
+		conflict := conflict
+		c.logger.Warnf("Route %s is not applied: %v", routeKey(conflict.route), conflict.err)
+		c.recorder.Eventf(&conflict.route, corev1.EventTypeWarning, reasonConflicted,
+			"Route is not applied: %v", conflict.err)
+		c.updateStatus(conflict.route, func(status *v1beta1.RouteStatus) {
+			markForeignConflict(status, conflict.err)
+		})
+	}
+	conflictedRoutes.WithLabelValues(router).Set(float64(len(conflicts) + len(foreign)))
+
+	table := c.routingTable(config, routes, fallback)
+	state.begin()
+	index, err := backend.Apply(table)
+	if err == nil {
+		state.set(config.backend, index, &table)
+	}
+
+	var registered map[string]bool
+	if registry, ok := backend.(routing.Registry); ok && err == nil {
+		var registryErr error
+		registered, registryErr = registry.RegisteredServices()
+		if registryErr != nil {
+			c.logger.Warnf("Failed to list services registered with backend %s: %v", config.backend, registryErr)
+		}
+	}
+
+	for _, route := range routes {
+		route := route
+		if err != nil {
+			c.recorder.Eventf(&route, corev1.EventTypeWarning, backendReason(config.backend, reasonRejected), "Failed to apply to %s: %v", config.backend, err)
+		} else {
+			// routes applied before with the same spec were only rewritten
+			if applied, ok := state.specs[routeKey(route)]; !ok || !equality.Semantic.DeepEqual(applied, route.Spec) {
+				c.recorder.Eventf(&route, corev1.EventTypeNormal, reasonApplied, "Applied to %s routing of service %s in index %d", config.backend, table.Service, index)
+			}
+			if registered != nil {
+				for _, missing := range missingDestinations(route, registered) {
+					c.recorder.Eventf(&route, corev1.EventTypeWarning, reasonDestinationNotFound, "Service %s is not registered with %s", missing, config.backend)
+				}
+			}
+		}
+
+		shadowed := shadowedBy(route, conflicts)
+		c.updateStatus(route, func(status *v1beta1.RouteStatus) {
+			if err != nil {
+				markSyncFailed(status, config.backend, err)
+				return
+			}
+			markSynced(status, config.backend, index)
+			if len(shadowed) > 0 {
+				markShadowing(status, shadowed)
+			}
+		})
+	}
+
+	c.updateRouterStatus(config, func(status *v1beta1.RouterStatus) {
+		status.AttachedRoutes = int32(len(routes))
+		status.ConflictedRoutes = int32(len(conflicts) + len(foreign))
+		status.RejectedRoutes = int32(len(rejected))
+		if err != nil {
+			status.Message = fmt.Sprintf("Failed to apply to %s: %v", config.backend, err)
+			return
+		}
+		status.AppliedIndex = index
+		status.Message = fmt.Sprintf("Applied to %s in index %d", config.backend, index)
+	})
+
+	if err != nil {
+		err = fmt.Errorf("failed to apply to %s: %v", config.backend, err)
+		state.fail(err)
+		return err
+	}
+
+	c.recordApplied(state, routes)
+	managedRoutes.WithLabelValues(router).Set(float64(len(routes)))
+	lastSyncTimestamp.WithLabelValues(router).SetToCurrentTime()
+	return c.removeFinalizers(terminating)
 }
 
-func checkCustomResourceType(obj interface{}, logger *zap.SugaredLogger) (v1beta1.Route, bool) {
-	var roll *v1beta1.Route
+// rejectAll marks all routes of a router that can't be applied invalid
+func (c Controller) rejectAll(routes []*v1beta1.Route, err error) {
+	for _, route := range routes {
+		c.recorder.Eventf(route, corev1.EventTypeWarning, reasonInvalid, "Route is rejected: %v", err)
+		c.updateStatus(*route, func(status *v1beta1.RouteStatus) {
+			markInvalid(status, err)
+		})
+	}
+}
+
+// releaseService removes everything prefix router applied to the service
+// a router pointed to before
+func (c Controller) releaseService(router string, state *appliedEntry) error {
+	if state.service == "" {
+		return nil
+	}
+
+	backend, ok := c.backends[state.backend]
+	if !ok {
+		return fmt.Errorf("failed to release service %s: backend %s is not available", state.service, state.backend)
+	}
+
+	c.logger.Infof("Removing routes of router %s from service %s in %s", router, state.service, state.backend)
+	if err := backend.Release(state.service); err != nil {
+		return fmt.Errorf("failed to release service %s: %v", state.service, err)
+	}
+
+	c.recordApplied(state, nil)
+	state.set("", 0, nil)
+	managedRoutes.WithLabelValues(router).Set(0)
+	return nil
+}
+
+// recordApplied remembers the spec each route was applied with,
+// logging routes that were removed or changed since the last apply
+func (c Controller) recordApplied(state *appliedEntry, routes []v1beta1.Route) {
+	current := make(map[string]v1beta1.RouteSpec, len(routes))
+	for _, route := range routes {
+		current[routeKey(route)] = route.Spec
+	}
+
+	for key, old := range state.specs {
+		spec, ok := current[key]
+		if !ok {
+			c.logger.Infof("Removed route %s %s -> %s", key, routePath(old), routeService(old))
+			delete(state.specs, key)
+			continue
+		}
+		if !equality.Semantic.DeepEqual(spec, old) {
+			c.logger.Infof("Changed route %s %s -> %s to %s -> %s", key, routePath(old), routeService(old), routePath(spec), routeService(spec))
+		}
+	}
+
+	for key, spec := range current {
+		state.specs[key] = spec
+	}
+}
+
+func checkRouterType(obj interface{}, logger *zap.SugaredLogger) (v1beta1.Router, bool) {
+	var router *v1beta1.Router
 	var ok bool
-	if roll, ok = obj.(*v1beta1.Route); !ok {
+	if router, ok = obj.(*v1beta1.Router); !ok {
 		logger.Errorf("Event Watch received an invalid object: %#v", obj)
-		return v1beta1.Route{}, false
+		return v1beta1.Router{}, false
 	}
-	return *roll, true
+	return *router, true
 }
 
-func checkSplitterConfigEntry(obj consulapi.ConfigEntry, logger *zap.SugaredLogger) (consulapi.ServiceSplitterConfigEntry, bool) {
-	var roll *consulapi.ServiceSplitterConfigEntry
+func checkCustomResourceType(obj interface{}, logger *zap.SugaredLogger) (v1beta1.Route, bool) {
+	var roll *v1beta1.Route
 	var ok bool
-	if roll, ok = obj.(*consulapi.ServiceSplitterConfigEntry); !ok {
-		logger.Errorf("Watch received an invalid object: %#v", obj)
-		return consulapi.ServiceSplitterConfigEntry{}, false
+	if roll, ok = obj.(*v1beta1.Route); !ok {
+		logger.Errorf("Event Watch received an invalid object: %#v", obj)
+		return v1beta1.Route{}, false
 	}
 	return *roll, true
 }
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"strings"
+
+	"github.com/oleksiyp/prefixrouter/pkg/routing"
+)
+
+// watchBackends starts watching the backends able to notice changes
+// made to their configuration by others
+func (c Controller) watchBackends(stopCh <-chan struct{}) {
+	for name, backend := range c.backends {
+		watcher, ok := backend.(routing.Watcher)
+		if !ok {
+			continue
+		}
+
+		name := name
+		go watcher.Watch(func() { c.checkDrift(name) }, stopCh)
+	}
+}
+
+// checkDrift triggers a reconcile of every router of the backend whose
+// live table drifted away from what prefix router applied. Writes by
+// prefix router itself and changes to foreign routes are not drift, the
+// backend notices the former while the write is still in progress or
+// with the revision of the write.
+func (c Controller) checkDrift(backendName string) {
+	backend := c.backends[backendName]
+	for _, router := range c.applied.routers() {
+		name, index, applied, applying := c.applied.get(router).get()
+		if name != backendName || applied == nil || applying {
+			// nothing applied yet or being applied right now, the
+			// reconcile writing it checks the outcome itself
+			continue
+		}
+
+		current, err := backend.Current(applied.Service)
+		if err != nil {
+			c.logger.Warnf("Failed to read the routing table of router %s: %v", router, err)
+			continue
+		}
+		if current != nil && current.Revision == index {
+			// the write of prefix router woke the watch up
+			continue
+		}
+
+		if diff := backend.Diff(applied, current); len(diff) > 0 {
+			c.logger.Warnf("Routing table of router %s was changed outside of prefix router, reconciling: %s",
+				router, strings.Join(diff, ", "))
+			driftTotal.WithLabelValues(router).Inc()
+			c.queue.Add(router)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"sort"
+
+	"github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1beta1"
+	prefixrouterscheme "github.com/oleksiyp/prefixrouter/pkg/client/clientset/versioned/scheme"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// eventComponent is the source of the events emitted on routes
+	eventComponent = "prefix-router"
+
+	reasonApplied             = "Applied"
+	reasonConflicted          = "Conflicted"
+	reasonInvalid             = "Invalid"
+	reasonDestinationNotFound = "DestinationNotFound"
+
+	// reasonRejected is prefixed with the backend name, e.g. ConsulRejected
+	reasonRejected = "Rejected"
+)
+
+// newEventRecorder creates a recorder publishing events through kubeClient
+func newEventRecorder(kubeClient kubernetes.Interface, logger *zap.SugaredLogger) record.EventRecorder {
+	// routes have to be known to the scheme to be referenced by events
+	utilruntime.Must(prefixrouterscheme.AddToScheme(scheme.Scheme))
+
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(logger.Debugf)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	return eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: eventComponent})
+}
+
+// missingDestinations returns the services the route sends traffic to
+// that are not registered with the backend
+func missingDestinations(route v1beta1.Route, registered map[string]bool) []string {
+	services := map[string]bool{}
+	if route.Spec.Service != "" {
+		services[route.Spec.Service] = true
+	}
+	for _, backend := range route.Spec.Backends {
+		services[backend.Service] = true
+	}
+
+	var missing []string
+	for service := range services {
+		if !registered[service] {
+			missing = append(missing, service)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
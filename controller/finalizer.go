@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1beta1"
+	"github.com/oleksiyp/prefixrouter/pkg/routing"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// routeFinalizer keeps a deleted route around until it is removed
+// from the routing table of its router
+const routeFinalizer = "prefixrouter.app/consul-route"
+
+// splitTerminating separates the routes being deleted
+func splitTerminating(all []*v1beta1.Route) ([]*v1beta1.Route, []*v1beta1.Route) {
+	var live, terminating []*v1beta1.Route
+	for _, route := range all {
+		if route.DeletionTimestamp != nil {
+			terminating = append(terminating, route)
+			continue
+		}
+		live = append(live, route)
+	}
+	return live, terminating
+}
+
+// addFinalizers adds the finalizer to the routes missing it, before any
+// of them is applied to a backend, and tells whether any route was updated
+func (c Controller) addFinalizers(routes []*v1beta1.Route) (bool, error) {
+	added := false
+	for _, route := range routes {
+		if hasFinalizer(route) {
+			continue
+		}
+
+		updated := route.DeepCopy()
+		updated.Finalizers = append(updated.Finalizers, routeFinalizer)
+		_, err := c.prefixRouterClient.PrefixrouterV1beta1().Routes(route.Namespace).Update(updated)
+		if err != nil {
+			return added, fmt.Errorf("failed to add finalizer to route %s: %v", routeKey(*route), err)
+		}
+		added = true
+	}
+	return added, nil
+}
+
+// removeFinalizers lets the deletion of the routes complete. It is only
+// called once a write without them succeeded.
+func (c Controller) removeFinalizers(routes []*v1beta1.Route) error {
+	for _, route := range routes {
+		if !hasFinalizer(route) {
+			continue
+		}
+
+		updated := route.DeepCopy()
+		updated.Finalizers = nil
+		for _, finalizer := range route.Finalizers {
+			if finalizer != routeFinalizer {
+				updated.Finalizers = append(updated.Finalizers, finalizer)
+			}
+		}
+		_, err := c.prefixRouterClient.PrefixrouterV1beta1().Routes(route.Namespace).Update(updated)
+		if err != nil {
+			return fmt.Errorf("failed to remove finalizer from route %s: %v", routeKey(*route), err)
+		}
+		c.logger.Infof("Route %s removed from its backend, finalizer released", routeKey(*route))
+	}
+	return nil
+}
+
+func hasFinalizer(route *v1beta1.Route) bool {
+	for _, finalizer := range route.Finalizers {
+		if finalizer == routeFinalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// resyncAll queues every router and releases the services that still
+// carry routes or entries written by this instance but have no router
+// anymore, e.g. because the Router was deleted while the controller was
+// down. Services of other instances are never released.
+// Routes deleted while the controller was down are dropped by the
+// reconcile of their router, which rewrites all owned routes.
+func (c Controller) resyncAll() error {
+	names := map[string]bool{}
+	if c.defaultRouter != "" {
+		names[c.defaultRouter] = true
+	}
+	routers, err := c.routerInformer.Lister().List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list routers: %v", err)
+	}
+	for _, router := range routers {
+		names[router.Name] = true
+	}
+	routes, err := c.routeInformer.Lister().List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list routes: %v", err)
+	}
+	for _, route := range routes {
+		names[c.routerOf(*route)] = true
+	}
+
+	// services still routed by a router, per backend
+	services := map[string]map[string]bool{}
+	for name := range names {
+		config, err := c.routerConfig(name)
+		if err != nil {
+			return err
+		}
+		if config != nil {
+			if services[config.backend] == nil {
+				services[config.backend] = map[string]bool{}
+			}
+			services[config.backend][config.serviceName] = true
+		}
+		c.queue.Add(name)
+	}
+
+	for name, backend := range c.backends {
+		inventory, ok := backend.(routing.Inventory)
+		if !ok {
+			continue
+		}
+
+		owned, err := inventory.Services()
+		if err != nil {
+			return fmt.Errorf("failed to list services of backend %s: %v", name, err)
+		}
+		for _, service := range owned {
+			if services[name][service] {
+				continue
+			}
+
+			c.logger.Infof("Service %s has routes of this prefix router in backend %s but no router, removing them", service, name)
+			if err := backend.Release(service); err != nil {
+				return fmt.Errorf("failed to release service %s: %v", service, err)
+			}
+		}
+	}
+	return nil
+}
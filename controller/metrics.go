@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	driftTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prefixrouter_drift_total",
+		Help: "Number of times the applied routing table was changed outside of prefix router.",
+	}, []string{"router"})
+
+	managedRoutes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "prefixrouter_routes",
+		Help: "Number of routes applied to the router config entry.",
+	}, []string{"router"})
+
+	conflictedRoutes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "prefixrouter_conflicted_routes",
+		Help: "Number of routes left out of the router config entry because of conflicts.",
+	}, []string{"router"})
+
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "prefixrouter_reconcile_duration_seconds",
+		Help:    "Duration of a full reconcile of the router config entry.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"router"})
+
+	informerEventsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "prefixrouter_informer_events_total",
+		Help: "Number of route events received from the informer by type.",
+	}, []string{"event"})
+
+	lastSyncTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "prefixrouter_last_sync_timestamp_seconds",
+		Help: "Unix time of the last successful sync, time() minus this is the time since the last sync.",
+	}, []string{"router"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		driftTotal,
+		managedRoutes,
+		conflictedRoutes,
+		reconcileDuration,
+		informerEventsTotal,
+		lastSyncTimestamp,
+	)
+}
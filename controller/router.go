@@ -0,0 +1,270 @@
+package controller
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1beta1"
+	"github.com/oleksiyp/prefixrouter/pkg/routing"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// routerConfig is the effective configuration of a router
+type routerConfig struct {
+	name        string
+	serviceName string
+	backend     string
+	spec        v1beta1.RouterSpec
+
+	// object is nil for the default router running without a Router
+	object *v1beta1.Router
+
+	// sharedWith is the older router routing the same service with
+	// the same backend, the router is rejected while it is set
+	sharedWith string
+}
+
+// routerConfig resolves a router by name, returning nil if it doesn't
+// exist. The default router doesn't need a Router object.
+func (c Controller) routerConfig(name string) (*routerConfig, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	router, err := c.routerInformer.Lister().Get(name)
+	if apierrors.IsNotFound(err) {
+		if name == c.defaultRouter {
+			return &routerConfig{name: name, serviceName: name, backend: c.defaultBackend}, nil
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get router %s: %v", name, err)
+	}
+
+	serviceName, backend := c.routedService(router)
+	sharedWith, err := c.serviceOwner(router)
+	if err != nil {
+		return nil, err
+	}
+	return &routerConfig{
+		name:        name,
+		serviceName: serviceName,
+		backend:     backend,
+		spec:        router.Spec,
+		object:      router,
+		sharedWith:  sharedWith,
+	}, nil
+}
+
+// routedService returns the service and the backend the router routes
+func (c Controller) routedService(router *v1beta1.Router) (string, string) {
+	serviceName := router.Spec.ServiceName
+	if serviceName == "" {
+		serviceName = router.Name
+	}
+	backend := router.Spec.Backend
+	if backend == "" {
+		backend = c.defaultBackend
+	}
+	return serviceName, backend
+}
+
+// serviceOwner returns the router that routes the same service as the
+// given one and is older, empty if there is none. Routers writing the
+// same service would replace each other's routes on every apply. The
+// default router running without a Router object is the oldest.
+func (c Controller) serviceOwner(router *v1beta1.Router) (string, error) {
+	serviceName, backend := c.routedService(router)
+
+	routers, err := c.routerInformer.Lister().List(labels.Everything())
+	if err != nil {
+		return "", fmt.Errorf("failed to list routers: %v", err)
+	}
+
+	defaultHasObject := false
+	var owner *v1beta1.Router
+	for _, other := range routers {
+		if other.Name == c.defaultRouter {
+			defaultHasObject = true
+		}
+		if other.Name == router.Name {
+			continue
+		}
+		if otherService, otherBackend := c.routedService(other); otherService != serviceName || otherBackend != backend {
+			continue
+		}
+		if olderRouter(other, router) && (owner == nil || olderRouter(other, owner)) {
+			owner = other
+		}
+	}
+
+	switch {
+	case c.defaultRouter != "" && !defaultHasObject && router.Name != c.defaultRouter &&
+		serviceName == c.defaultRouter && backend == c.defaultBackend:
+		return c.defaultRouter, nil
+	case owner != nil:
+		return owner.Name, nil
+	default:
+		return "", nil
+	}
+}
+
+func olderRouter(a, b *v1beta1.Router) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+// admit splits routes into the ones the router policy allows to attach
+// and the ones it rejects
+func (r routerConfig) admit(routes []*v1beta1.Route) ([]*v1beta1.Route, []routeRejection) {
+	var admitted []*v1beta1.Route
+	var rejected []routeRejection
+	for _, route := range routes {
+		if err := r.checkPolicy(*route); err != nil {
+			rejected = append(rejected, routeRejection{route: *route, err: err})
+			continue
+		}
+		admitted = append(admitted, route)
+	}
+	return admitted, rejected
+}
+
+func (r routerConfig) checkPolicy(route v1beta1.Route) error {
+	if len(r.spec.AllowedNamespaces) > 0 && !contains(r.spec.AllowedNamespaces, route.Namespace) {
+		return fmt.Errorf("namespace %s is not allowed to attach routes to router %s", route.Namespace, r.name)
+	}
+
+	spec := route.Spec
+	for _, reserved := range r.spec.ReservedPrefixes {
+		switch {
+		case spec.PathRegex != "":
+			if regexMayMatch(spec.PathRegex, reserved) {
+				return fmt.Errorf("path regex %s may match prefix %s reserved by router %s", spec.PathRegex, reserved, r.name)
+			}
+		case spec.Prefix != "":
+			if prefixMayMatch(spec.Prefix, reserved) {
+				return fmt.Errorf("prefix %s may match prefix %s reserved by router %s", spec.Prefix, reserved, r.name)
+			}
+		case underPrefix(spec.PathExact, reserved):
+			return fmt.Errorf("path %s is under prefix %s reserved by router %s", routePath(spec), reserved, r.name)
+		}
+	}
+	return nil
+}
+
+// underPrefix tells whether path is the prefix or below it, comparing
+// whole path segments so that /admin doesn't cover /administrators
+func underPrefix(path, prefix string) bool {
+	if path == "" {
+		return false
+	}
+	parent := strings.TrimSuffix(prefix, "/")
+	return path == parent || strings.HasPrefix(path, parent+"/")
+}
+
+// prefixMayMatch tells whether a path starting with literal may be under
+// the prefix. Paths are matched as plain strings, so /adm matches
+// /admin too.
+func prefixMayMatch(literal, prefix string) bool {
+	return underPrefix(literal, prefix) || strings.HasPrefix(strings.TrimSuffix(prefix, "/"), literal)
+}
+
+// regexMayMatch tells whether the regular expression may match a path
+// under the prefix. Only the literal prefix of the expression is
+// considered, so expressions starting with anything but a literal
+// path are assumed to match everything.
+func regexMayMatch(expr, prefix string) bool {
+	// the whole path has to match anyway, an anchor changes nothing
+	re, err := regexp.Compile(strings.TrimPrefix(expr, "^"))
+	if err != nil {
+		return true
+	}
+	literal, _ := re.LiteralPrefix()
+	return prefixMayMatch(literal, prefix)
+}
+
+// withDefaults fills the destination settings the route leaves unset
+// from the router defaults
+func (r routerConfig) withDefaults(spec v1beta1.RouteSpec) v1beta1.RouteSpec {
+	defaults := r.spec.Defaults
+	if spec.RequestTimeout == nil {
+		spec.RequestTimeout = defaults.RequestTimeout
+	}
+	if spec.NumRetries == nil {
+		spec.NumRetries = &defaults.NumRetries
+	}
+	if spec.RetryOnConnectFailure == nil {
+		spec.RetryOnConnectFailure = &defaults.RetryOnConnectFailure
+	}
+	if spec.RetryOnStatusCodes == nil {
+		spec.RetryOnStatusCodes = &defaults.RetryOnStatusCodes
+	}
+	return spec
+}
+
+// catchAll returns the route sending requests no other route matches
+// to the default service of the router
+func (r routerConfig) catchAll() []routing.Route {
+	if r.spec.DefaultService == "" {
+		return nil
+	}
+
+	return []routing.Route{{
+		Match: routing.Match{
+			PathPrefix: "/",
+		},
+		Destination: routing.Destination{
+			Targets: []routing.Target{{
+				Service: r.spec.DefaultService,
+				Weight:  100,
+			}},
+		},
+	}}
+}
+
+// updateRouterStatus applies modify to the latest known state of the
+// Router object and writes its status subresource if anything changed
+func (c Controller) updateRouterStatus(config *routerConfig, modify func(status *v1beta1.RouterStatus)) {
+	if config.object == nil {
+		return
+	}
+
+	latest, err := c.routerInformer.Lister().Get(config.name)
+	if apierrors.IsNotFound(err) {
+		return
+	}
+	if err != nil {
+		c.logger.Errorf("Failed to get router %s: %v", config.name, err)
+		return
+	}
+
+	status := latest.Status.DeepCopy()
+	status.ObservedGeneration = config.object.Generation
+	modify(status)
+
+	if equality.Semantic.DeepEqual(*status, latest.Status) {
+		return
+	}
+
+	updated := latest.DeepCopy()
+	updated.Status = *status
+	_, err = c.prefixRouterClient.PrefixrouterV1beta1().Routers().UpdateStatus(updated)
+	if err != nil {
+		c.logger.Errorf("Failed to update status of router %s: %v", config.name, err)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
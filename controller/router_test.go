@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCheckPolicy(t *testing.T) {
+	config := routerConfig{
+		name: "router1",
+		spec: v1beta1.RouterSpec{
+			AllowedNamespaces: []string{"default", "team-a"},
+			ReservedPrefixes:  []string{"/admin", "/internal/"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		namespace string
+		spec      v1beta1.RouteSpec
+		wantErr   string
+	}{
+		{name: "allowed prefix", spec: v1beta1.RouteSpec{Prefix: "/api"}},
+		{name: "similar prefix", spec: v1beta1.RouteSpec{Prefix: "/administrators"}},
+		{name: "parent prefix", spec: v1beta1.RouteSpec{Prefix: "/"}, wantErr: "prefix / may match prefix /admin"},
+		{name: "partial prefix", spec: v1beta1.RouteSpec{Prefix: "/adm"}, wantErr: "may match prefix /admin"},
+		{name: "reserved prefix", spec: v1beta1.RouteSpec{Prefix: "/admin"}, wantErr: "reserved by router router1"},
+		{name: "reserved prefix with slash", spec: v1beta1.RouteSpec{Prefix: "/admin/"}, wantErr: "reserved"},
+		{name: "under reserved prefix", spec: v1beta1.RouteSpec{Prefix: "/admin/users"}, wantErr: "reserved"},
+		{name: "reserved prefix ending in slash", spec: v1beta1.RouteSpec{Prefix: "/internal"}, wantErr: "reserved"},
+		{name: "exact path under reserved prefix", spec: v1beta1.RouteSpec{PathExact: "/internal/health"}, wantErr: "reserved"},
+		{name: "exact similar path", spec: v1beta1.RouteSpec{PathExact: "/internals"}},
+		{name: "regex under reserved prefix", spec: v1beta1.RouteSpec{PathRegex: "/admin/.*"}, wantErr: "may match prefix /admin"},
+		{name: "anchored regex", spec: v1beta1.RouteSpec{PathRegex: "^/admin/.*"}, wantErr: "may match"},
+		{name: "regex reaching reserved prefix", spec: v1beta1.RouteSpec{PathRegex: "/adm(in)?/.*"}, wantErr: "may match"},
+		{name: "regex without literal prefix", spec: v1beta1.RouteSpec{PathRegex: ".*"}, wantErr: "may match"},
+		{name: "regex elsewhere", spec: v1beta1.RouteSpec{PathRegex: "/api/v[0-9]+/.*"}},
+		{name: "regex on similar path", spec: v1beta1.RouteSpec{PathRegex: "/administrators/.*"}},
+		{name: "namespace not allowed", namespace: "team-b", spec: v1beta1.RouteSpec{Prefix: "/api"}, wantErr: "namespace team-b is not allowed"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			namespace := test.namespace
+			if namespace == "" {
+				namespace = "default"
+			}
+			route := v1beta1.Route{
+				ObjectMeta: metav1.ObjectMeta{Name: "route1", Namespace: namespace},
+				Spec:       test.spec,
+			}
+
+			err := config.checkPolicy(route)
+			switch {
+			case test.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case test.wantErr != "" && err == nil:
+				t.Errorf("got no error, want %q", test.wantErr)
+			case test.wantErr != "" && !strings.Contains(err.Error(), test.wantErr):
+				t.Errorf("got error %q, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithDefaults(t *testing.T) {
+	config := routerConfig{
+		spec: v1beta1.RouterSpec{
+			Defaults: v1beta1.RouteDefaults{
+				NumRetries:            2,
+				RetryOnConnectFailure: true,
+				RetryOnStatusCodes:    []uint32{503},
+			},
+		},
+	}
+	zero := uint32(0)
+	five := uint32(5)
+	no := false
+
+	tests := []struct {
+		name                      string
+		spec                      v1beta1.RouteSpec
+		wantRetries               uint32
+		wantRetryOnConnectFailure bool
+		wantRetryOnStatusCodes    []uint32
+	}{
+		{
+			name:                      "inherited",
+			spec:                      v1beta1.RouteSpec{},
+			wantRetries:               2,
+			wantRetryOnConnectFailure: true,
+			wantRetryOnStatusCodes:    []uint32{503},
+		},
+		{
+			name:                      "overridden",
+			spec:                      v1beta1.RouteSpec{NumRetries: &five, RetryOnStatusCodes: &[]uint32{502}},
+			wantRetries:               5,
+			wantRetryOnConnectFailure: true,
+			wantRetryOnStatusCodes:    []uint32{502},
+		},
+		{
+			name:                      "opted out",
+			spec:                      v1beta1.RouteSpec{NumRetries: &zero, RetryOnConnectFailure: &no, RetryOnStatusCodes: &[]uint32{}},
+			wantRetries:               0,
+			wantRetryOnConnectFailure: false,
+			wantRetryOnStatusCodes:    []uint32{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := config.withDefaults(test.spec)
+			if *spec.NumRetries != test.wantRetries {
+				t.Errorf("got numRetries %d, want %d", *spec.NumRetries, test.wantRetries)
+			}
+			if *spec.RetryOnConnectFailure != test.wantRetryOnConnectFailure {
+				t.Errorf("got retryOnConnectFailure %t, want %t", *spec.RetryOnConnectFailure, test.wantRetryOnConnectFailure)
+			}
+			if !reflect.DeepEqual(*spec.RetryOnStatusCodes, test.wantRetryOnStatusCodes) {
+				t.Errorf("got retryOnStatusCodes %v, want %v", *spec.RetryOnStatusCodes, test.wantRetryOnStatusCodes)
+			}
+		})
+	}
+}
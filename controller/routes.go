@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1beta1"
+	"github.com/oleksiyp/prefixrouter/pkg/routing"
+)
+
+type routeConflict struct {
+	route  v1beta1.Route
+	winner v1beta1.Route
+}
+
+type routeRejection struct {
+	route v1beta1.Route
+	err   error
+}
+
+// validRoutes splits routes into the ones that can be applied
+// and the ones Consul would reject
+func validRoutes(all []*v1beta1.Route) ([]*v1beta1.Route, []routeRejection) {
+	var valid []*v1beta1.Route
+	var rejected []routeRejection
+	for _, route := range all {
+		if err := validateRoute(route.Spec); err != nil {
+			rejected = append(rejected, routeRejection{route: *route, err: err})
+			continue
+		}
+		valid = append(valid, route)
+	}
+	return valid, rejected
+}
+
+// desiredRoutes picks a single route per match and returns them oldest
+// first together with the routes that lost their match. When routes
+// share a match the oldest one wins.
+func desiredRoutes(all []*v1beta1.Route) ([]v1beta1.Route, []routeConflict) {
+	candidates := make([]v1beta1.Route, 0, len(all))
+	for _, route := range all {
+		candidates = append(candidates, *route)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+			return a.CreationTimestamp.Before(&b.CreationTimestamp)
+		}
+		return routeKey(a) < routeKey(b)
+	})
+
+	byMatch := make(map[string]v1beta1.Route)
+	var routes []v1beta1.Route
+	var conflicts []routeConflict
+	for _, route := range candidates {
+		key := matchKey(route.Spec)
+		if winner, ok := byMatch[key]; ok {
+			conflicts = append(conflicts, routeConflict{route: route, winner: winner})
+			continue
+		}
+		byMatch[key] = route
+		routes = append(routes, route)
+	}
+
+	return routes, conflicts
+}
+
+// foreignConflicts splits routes into the ones the backend can apply and
+// the ones that would overwrite configuration prefix router doesn't own.
+// It also returns the fallback routes of the router that can be applied.
+func (c Controller) foreignConflicts(checker routing.Checker, config *routerConfig, routes []v1beta1.Route) ([]v1beta1.Route, []routing.Route, []routeRejection, error) {
+	var declared []routing.Subset
+	candidates := config.catchAll()
+	for _, route := range routes {
+		candidates = append(candidates, routingRoute(route))
+		for _, subset := range route.Spec.Subsets {
+			declared = append(declared, routing.Subset{
+				Service:    subset.Service,
+				Name:       subset.Name,
+				DeclaredBy: routeKey(route),
+			})
+		}
+	}
+	subsets, err := checker.SubsetConflicts(config.serviceName, declared)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	matches, err := checker.RouteConflicts(config.serviceName, candidates)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var kept []v1beta1.Route
+	var conflicts []routeRejection
+	for _, route := range routes {
+		conflict := matches[routeKey(route)]
+		for _, subset := range route.Spec.Subsets {
+			if err, ok := subsets[subset.Service]; ok && conflict == nil {
+				conflict = fmt.Errorf("subset %s/%s can't be declared: %v", subset.Service, subset.Name, err)
+			}
+		}
+		if conflict != nil {
+			conflicts = append(conflicts, routeRejection{route: route, err: conflict})
+			continue
+		}
+		kept = append(kept, route)
+	}
+
+	// the fallback routes are the only ones without a name
+	fallback := config.catchAll()
+	if err, ok := matches[""]; ok {
+		c.logger.Warnf("Default service route of router %s is not applied: %v", config.name, err)
+		fallback = nil
+	}
+	return kept, fallback, conflicts, nil
+}
+
+// shadowedBy returns the routes that lost their match to the given route
+func shadowedBy(route v1beta1.Route, conflicts []routeConflict) []v1beta1.Route {
+	var shadowed []v1beta1.Route
+	for _, conflict := range conflicts {
+		if routeKey(conflict.winner) == routeKey(route) {
+			shadowed = append(shadowed, conflict.route)
+		}
+	}
+	return shadowed
+}
+
+// routePath returns whichever path the route matches on
+func routePath(spec v1beta1.RouteSpec) string {
+	switch {
+	case spec.PathExact != "":
+		return spec.PathExact
+	case spec.PathRegex != "":
+		return spec.PathRegex
+	default:
+		return spec.Prefix
+	}
+}
+
+// matchKey identifies the traffic a route takes, routes with equal
+// keys conflict with each other
+func matchKey(spec v1beta1.RouteSpec) string {
+	return routingMatch(spec).Key()
+}
+
+// routeService describes where the route sends traffic
+func routeService(spec v1beta1.RouteSpec) string {
+	if len(spec.Backends) == 0 {
+		return subsetName(spec.Service, spec.ServiceSubset)
+	}
+
+	backends := make([]string, 0, len(spec.Backends))
+	for _, backend := range spec.Backends {
+		backends = append(backends, fmt.Sprintf("%s=%d%%", subsetName(backend.Service, backend.ServiceSubset), backend.Weight))
+	}
+	return strings.Join(backends, ",")
+}
+
+func subsetName(service, subset string) string {
+	if subset == "" {
+		return service
+	}
+	return subset + "." + service
+}
+
+func routeKey(route v1beta1.Route) string {
+	return route.Namespace + "/" + route.Name
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1beta1"
+	"github.com/oleksiyp/prefixrouter/pkg/routing"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeChecker reports the subsets of the services in subsets and the
+// routes named in routes
+type fakeChecker struct {
+	subsets map[string]error
+	routes  map[string]error
+}
+
+func (f fakeChecker) SubsetConflicts(service string, subsets []routing.Subset) (map[string]error, error) {
+	return f.subsets, nil
+}
+
+func (f fakeChecker) RouteConflicts(service string, routes []routing.Route) (map[string]error, error) {
+	return f.routes, nil
+}
+
+func TestForeignConflicts(t *testing.T) {
+	route := func(name string, subsets ...v1beta1.SubsetDefinition) v1beta1.Route {
+		return v1beta1.Route{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       v1beta1.RouteSpec{Prefix: "/" + name, Service: "web", Subsets: subsets},
+		}
+	}
+	routes := []v1beta1.Route{
+		route("plain"),
+		route("foreign", v1beta1.SubsetDefinition{Service: "api", Name: "v2"}),
+		route("managed", v1beta1.SubsetDefinition{Service: "web", Name: "v2"}),
+		route("mixed", v1beta1.SubsetDefinition{Service: "web", Name: "v1"}, v1beta1.SubsetDefinition{Service: "api", Name: "v1"}),
+		route("taken"),
+	}
+	checker := fakeChecker{
+		subsets: map[string]error{"api": errors.New("service-resolver api exists")},
+		routes: map[string]error{
+			"default/taken": errors.New("same match on /taken"),
+			"":              errors.New("same match on /"),
+		},
+	}
+	config := &routerConfig{
+		name:        "router1",
+		serviceName: "router1",
+		spec:        v1beta1.RouterSpec{DefaultService: "web"},
+	}
+	c := Controller{logger: zap.NewNop().Sugar()}
+
+	kept, fallback, conflicts, err := c.foreignConflicts(checker, config, routes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var keptNames, conflictNames []string
+	for _, route := range kept {
+		keptNames = append(keptNames, route.Name)
+	}
+	for _, conflict := range conflicts {
+		conflictNames = append(conflictNames, conflict.route.Name)
+	}
+	if want := []string{"plain", "managed"}; !reflect.DeepEqual(keptNames, want) {
+		t.Errorf("got kept %v, want %v", keptNames, want)
+	}
+	if want := []string{"foreign", "mixed", "taken"}; !reflect.DeepEqual(conflictNames, want) {
+		t.Errorf("got conflicts %v, want %v", conflictNames, want)
+	}
+	if len(fallback) != 0 {
+		t.Errorf("got fallback %+v, want none", fallback)
+	}
+}
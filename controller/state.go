@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1beta1"
+	"github.com/oleksiyp/prefixrouter/pkg/routing"
+)
+
+// appliedEntry is the last routing table prefix router applied for a
+// router, shared between the reconcile worker, the drift watcher and
+// health checks
+type appliedEntry struct {
+	sync.Mutex
+	backend string
+	service string
+	index   uint64
+	table   *routing.Table
+	specs   map[string]v1beta1.RouteSpec
+	err     error
+
+	// applying is set while a table is being written, the backend
+	// then has neither the old table nor the new one for a while
+	applying bool
+}
+
+// begin marks the start of a write, it ends with set or fail
+func (a *appliedEntry) begin() {
+	a.Lock()
+	defer a.Unlock()
+	a.applying = true
+}
+
+func (a *appliedEntry) set(backend string, index uint64, table *routing.Table) {
+	a.Lock()
+	defer a.Unlock()
+	a.backend = backend
+	a.service = ""
+	if table != nil {
+		a.service = table.Service
+	}
+	a.index = index
+	a.table = table
+	a.err = nil
+	a.applying = false
+}
+
+func (a *appliedEntry) fail(err error) {
+	a.Lock()
+	defer a.Unlock()
+	a.err = err
+	a.applying = false
+}
+
+func (a *appliedEntry) lastError() error {
+	a.Lock()
+	defer a.Unlock()
+	return a.err
+}
+
+func (a *appliedEntry) get() (backend string, index uint64, table *routing.Table, applying bool) {
+	a.Lock()
+	defer a.Unlock()
+	return a.backend, a.index, a.table, a.applying
+}
+
+// routerStates holds the applied state of every router seen so far
+type routerStates struct {
+	sync.Mutex
+	states map[string]*appliedEntry
+}
+
+func newRouterStates() *routerStates {
+	return &routerStates{states: make(map[string]*appliedEntry)}
+}
+
+// get returns the state of the router, creating it on first use
+func (r *routerStates) get(router string) *appliedEntry {
+	r.Lock()
+	defer r.Unlock()
+
+	state, ok := r.states[router]
+	if !ok {
+		state = &appliedEntry{specs: make(map[string]v1beta1.RouteSpec)}
+		r.states[router] = state
+	}
+	return state
+}
+
+// remove forgets the state of a router that no longer exists
+func (r *routerStates) remove(router string) {
+	r.Lock()
+	defer r.Unlock()
+	delete(r.states, router)
+}
+
+// routers returns the names of the known routers in order
+func (r *routerStates) routers() []string {
+	r.Lock()
+	defer r.Unlock()
+
+	routers := make([]string, 0, len(r.states))
+	for router := range r.states {
+		routers = append(routers, router)
+	}
+	sort.Strings(routers)
+	return routers
+}
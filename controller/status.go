@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// updateStatus applies modify to the latest known state of the route
+// and writes the status subresource if anything changed
+func (c Controller) updateStatus(route v1beta1.Route, modify func(status *v1beta1.RouteStatus)) {
+	latest, err := c.routeInformer.Lister().Routes(route.Namespace).Get(route.Name)
+	if apierrors.IsNotFound(err) {
+		return
+	}
+	if err != nil {
+		c.logger.Errorf("Failed to get route %s/%s: %v", route.Namespace, route.Name, err)
+		return
+	}
+
+	status := latest.Status.DeepCopy()
+	status.ObservedGeneration = route.Generation
+	modify(status)
+
+	if equality.Semantic.DeepEqual(*status, latest.Status) {
+		return
+	}
+
+	updated := latest.DeepCopy()
+	updated.Status = *status
+	_, err = c.prefixRouterClient.PrefixrouterV1beta1().Routes(route.Namespace).UpdateStatus(updated)
+	if err != nil {
+		c.logger.Errorf("Failed to update status of route %s/%s: %v", route.Namespace, route.Name, err)
+	}
+}
+
+func markSynced(status *v1beta1.RouteStatus, backend string, index uint64) {
+	status.AppliedIndex = index
+	status.Message = fmt.Sprintf("Applied to %s in index %d", backend, index)
+	setCondition(status, v1beta1.RouteSynced, corev1.ConditionTrue, "Applied", "")
+	setCondition(status, v1beta1.RouteConflicted, corev1.ConditionFalse, "", "")
+	setCondition(status, v1beta1.RouteReady, corev1.ConditionTrue, "Applied", "")
+}
+
+func markSyncFailed(status *v1beta1.RouteStatus, backend string, err error) {
+	status.Message = fmt.Sprintf("Failed to apply to %s: %v", backend, err)
+	setCondition(status, v1beta1.RouteSynced, corev1.ConditionFalse, backendReason(backend, "Error"), err.Error())
+	setCondition(status, v1beta1.RouteReady, corev1.ConditionFalse, backendReason(backend, "Error"), err.Error())
+}
+
+// backendReason prefixes a reason with the backend name,
+// e.g. ConsulError for the consul backend
+func backendReason(backend, reason string) string {
+	if backend == "" {
+		return reason
+	}
+	return strings.ToUpper(backend[:1]) + backend[1:] + reason
+}
+
+func markConflicted(status *v1beta1.RouteStatus, winner v1beta1.Route) {
+	message := fmt.Sprintf("Same match on path %s is claimed by older route %s/%s", routePath(winner.Spec), winner.Namespace, winner.Name)
+	status.Message = message
+	setCondition(status, v1beta1.RouteSynced, corev1.ConditionFalse, "Conflicted", message)
+	setCondition(status, v1beta1.RouteConflicted, corev1.ConditionTrue, "MatchClaimed", message)
+	setCondition(status, v1beta1.RouteReady, corev1.ConditionFalse, "Conflicted", message)
+}
+
+// markForeignConflict records that applying the route would overwrite
+// configuration prefix router doesn't own
+func markForeignConflict(status *v1beta1.RouteStatus, err error) {
+	message := fmt.Sprintf("Conflicts with configuration not managed by prefix router: %v", err)
+	status.Message = message
+	setCondition(status, v1beta1.RouteSynced, corev1.ConditionFalse, "Conflicted", message)
+	setCondition(status, v1beta1.RouteConflicted, corev1.ConditionTrue, "ForeignConfig", message)
+	setCondition(status, v1beta1.RouteReady, corev1.ConditionFalse, "Conflicted", message)
+}
+
+func markInvalid(status *v1beta1.RouteStatus, err error) {
+	message := fmt.Sprintf("Invalid route: %v", err)
+	status.Message = message
+	setCondition(status, v1beta1.RouteSynced, corev1.ConditionFalse, "Invalid", message)
+	setCondition(status, v1beta1.RouteReady, corev1.ConditionFalse, "Invalid", message)
+}
+
+// markShadowing records on the route that owns a match which
+// younger routes lost the match to it
+func markShadowing(status *v1beta1.RouteStatus, shadowed []v1beta1.Route) {
+	keys := make([]string, 0, len(shadowed))
+	for _, route := range shadowed {
+		keys = append(keys, route.Namespace+"/"+route.Name)
+	}
+
+	message := fmt.Sprintf("Same match is also claimed by %s", strings.Join(keys, ", "))
+	status.Message += ". " + message
+	setCondition(status, v1beta1.RouteConflicted, corev1.ConditionTrue, "MatchOwner", message)
+}
+
+// setCondition adds or updates a condition, keeping the transition time
+// unless the condition status actually changed
+func setCondition(
+	status *v1beta1.RouteStatus,
+	conditionType v1beta1.RouteConditionType,
+	conditionStatus corev1.ConditionStatus,
+	reason, message string,
+) {
+	condition := v1beta1.RouteCondition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+
+	for i, existing := range status.Conditions {
+		if existing.Type != conditionType {
+			continue
+		}
+		if existing.Status == conditionStatus {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+		status.Conditions[i] = condition
+		return
+	}
+
+	status.Conditions = append(status.Conditions, condition)
+}
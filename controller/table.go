@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"sort"
+
+	"github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1beta1"
+	"github.com/oleksiyp/prefixrouter/pkg/routing"
+)
+
+// routingTable computes the table of the router from the routes picked
+// by desiredRoutes and the fallback routes. When routes declare the same
+// subset differently the oldest route wins.
+func (c Controller) routingTable(config *routerConfig, routes []v1beta1.Route, fallback []routing.Route) routing.Table {
+	table := routing.Table{
+		Service:  config.serviceName,
+		Fallback: fallback,
+	}
+
+	byAge := append([]v1beta1.Route{}, routes...)
+	sort.SliceStable(byAge, func(i, j int) bool {
+		return byAge[i].CreationTimestamp.Before(&byAge[j].CreationTimestamp)
+	})
+
+	var declared []routing.Subset
+	for _, route := range byAge {
+		route.Spec = config.withDefaults(route.Spec)
+		table.Routes = append(table.Routes, routingRoute(route))
+
+		for _, subset := range route.Spec.Subsets {
+			declared = append(declared, routing.Subset{
+				Service:     subset.Service,
+				Name:        subset.Name,
+				Filter:      subset.Filter,
+				OnlyPassing: subset.OnlyPassing,
+				DeclaredBy:  routeKey(route),
+			})
+		}
+	}
+	routing.Sort(table.Routes)
+
+	var redefined []routing.Subset
+	table.Subsets, redefined = routing.MergeSubsets(declared)
+	for _, subset := range redefined {
+		c.logger.Warnf("Route %s redefines subset %s/%s declared by %s, keeping the older one",
+			subset.DeclaredBy, subset.Service, subset.Name, declaredBy(table.Subsets, subset))
+	}
+	return table
+}
+
+// declaredBy returns the route whose declaration of the subset was kept
+func declaredBy(subsets []routing.Subset, redefined routing.Subset) string {
+	for _, subset := range subsets {
+		if subset.Service == redefined.Service && subset.Name == redefined.Name {
+			return subset.DeclaredBy
+		}
+	}
+	return ""
+}
+
+// routingRoute converts a route to its backend-neutral form
+func routingRoute(route v1beta1.Route) routing.Route {
+	spec := route.Spec
+	converted := routing.Route{
+		Name:     routeKey(route),
+		Match:    routingMatch(spec),
+		Priority: spec.Priority,
+		Destination: routing.Destination{
+			PrefixRewrite:   spec.PrefixRewrite,
+			RequestHeaders:  routingHeaderModifiers(spec.RequestHeaders),
+			ResponseHeaders: routingHeaderModifiers(spec.ResponseHeaders),
+		},
+	}
+	if spec.RequestTimeout != nil {
+		converted.Destination.RequestTimeout = spec.RequestTimeout.Duration
+	}
+	if spec.RetryOnStatusCodes != nil {
+		converted.Destination.RetryOnStatusCodes = *spec.RetryOnStatusCodes
+	}
+	if spec.NumRetries != nil {
+		converted.Destination.NumRetries = *spec.NumRetries
+	}
+	if spec.RetryOnConnectFailure != nil {
+		converted.Destination.RetryOnConnectFailure = *spec.RetryOnConnectFailure
+	}
+
+	if len(spec.Backends) == 0 {
+		converted.Destination.Targets = []routing.Target{{
+			Service: spec.Service,
+			Subset:  spec.ServiceSubset,
+			Weight:  100,
+		}}
+		return converted
+	}
+	for _, backend := range spec.Backends {
+		converted.Destination.Targets = append(converted.Destination.Targets, routing.Target{
+			Service: backend.Service,
+			Subset:  backend.ServiceSubset,
+			Weight:  backend.Weight,
+		})
+	}
+	return converted
+}
+
+func routingMatch(spec v1beta1.RouteSpec) routing.Match {
+	match := routing.Match{
+		PathPrefix: spec.Prefix,
+		PathExact:  spec.PathExact,
+		PathRegex:  spec.PathRegex,
+		Methods:    spec.Methods,
+	}
+
+	for _, header := range spec.Headers {
+		match.Headers = append(match.Headers, routing.HeaderMatch{
+			Name:    header.Name,
+			Present: header.Present,
+			Exact:   header.Exact,
+			Prefix:  header.Prefix,
+			Suffix:  header.Suffix,
+			Regex:   header.Regex,
+			Invert:  header.Invert,
+		})
+	}
+
+	for _, param := range spec.QueryParams {
+		match.QueryParams = append(match.QueryParams, routing.QueryParamMatch{
+			Name:    param.Name,
+			Present: param.Present,
+			Exact:   param.Exact,
+			Regex:   param.Regex,
+		})
+	}
+	return match
+}
+
+func routingHeaderModifiers(modifiers *v1beta1.HeaderModifiers) *routing.HeaderModifiers {
+	if modifiers == nil {
+		return nil
+	}
+	return &routing.HeaderModifiers{
+		Add:    modifiers.Add,
+		Set:    modifiers.Set,
+		Remove: modifiers.Remove,
+	}
+}
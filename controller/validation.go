@@ -0,0 +1,228 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1beta1"
+	"golang.org/x/net/http/httpguts"
+)
+
+var httpMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"POST":    true,
+	"PUT":     true,
+	"PATCH":   true,
+	"DELETE":  true,
+	"CONNECT": true,
+	"OPTIONS": true,
+	"TRACE":   true,
+}
+
+// validateRoute catches specs that Consul would refuse, as a single
+// bad route makes Consul reject the whole config entry
+func validateRoute(spec v1beta1.RouteSpec) error {
+	switch countSet(false, spec.Prefix, spec.PathExact, spec.PathRegex) {
+	case 0:
+		return fmt.Errorf("one of prefix, pathExact or pathRegex is required")
+	case 1:
+	default:
+		return fmt.Errorf("prefix, pathExact and pathRegex are mutually exclusive")
+	}
+	if spec.Prefix != "" && !strings.HasPrefix(spec.Prefix, "/") {
+		return fmt.Errorf("prefix %q has to start with /", spec.Prefix)
+	}
+	if spec.PathExact != "" && !strings.HasPrefix(spec.PathExact, "/") {
+		return fmt.Errorf("pathExact %q has to start with /", spec.PathExact)
+	}
+	if err := validateRegex("pathRegex", spec.PathRegex); err != nil {
+		return err
+	}
+	if err := validateBackends(spec); err != nil {
+		return err
+	}
+
+	for _, header := range spec.Headers {
+		if header.Name == "" {
+			return fmt.Errorf("header match without a name")
+		}
+		set := countSet(header.Present, header.Exact, header.Prefix, header.Suffix, header.Regex)
+		if set != 1 {
+			return fmt.Errorf("header %s has to set exactly one of present, exact, prefix, suffix or regex", header.Name)
+		}
+		if err := validateRegex("header "+header.Name, header.Regex); err != nil {
+			return err
+		}
+	}
+
+	for _, param := range spec.QueryParams {
+		if param.Name == "" {
+			return fmt.Errorf("query parameter match without a name")
+		}
+		set := countSet(param.Present, param.Exact, param.Regex)
+		if set != 1 {
+			return fmt.Errorf("query parameter %s has to set exactly one of present, exact or regex", param.Name)
+		}
+		if err := validateRegex("query parameter "+param.Name, param.Regex); err != nil {
+			return err
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, method := range spec.Methods {
+		if !httpMethods[method] {
+			return fmt.Errorf("unknown HTTP method %q", method)
+		}
+		if seen[method] {
+			return fmt.Errorf("duplicate HTTP method %s", method)
+		}
+		seen[method] = true
+	}
+
+	if err := validateSubsets(spec); err != nil {
+		return err
+	}
+
+	return validateDestination(spec)
+}
+
+func validateBackends(spec v1beta1.RouteSpec) error {
+	if spec.Service != "" && len(spec.Backends) > 0 {
+		return fmt.Errorf("service and backends are mutually exclusive")
+	}
+	if spec.ServiceSubset != "" && spec.Service == "" {
+		return fmt.Errorf("serviceSubset requires service")
+	}
+	if spec.Service == "" && len(spec.Backends) == 0 {
+		return fmt.Errorf("one of service or backends is required")
+	}
+
+	var total int32
+	for _, backend := range spec.Backends {
+		if backend.Service == "" {
+			return fmt.Errorf("backend without a service")
+		}
+		if backend.Weight < 0 || backend.Weight > 100 {
+			return fmt.Errorf("backend %s weight %d is out of 0-100 range", backend.Service, backend.Weight)
+		}
+		total += backend.Weight
+	}
+	if len(spec.Backends) > 0 && total != 100 {
+		return fmt.Errorf("backend weights add up to %d instead of 100", total)
+	}
+	return nil
+}
+
+// subsetNamePattern is what Consul accepts as a subset name
+var subsetNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+func validateSubsets(spec v1beta1.RouteSpec) error {
+	seen := make(map[string]bool)
+	for _, subset := range spec.Subsets {
+		if subset.Service == "" {
+			return fmt.Errorf("subset %s without a service", subset.Name)
+		}
+		if !subsetNamePattern.MatchString(subset.Name) {
+			return fmt.Errorf("subset name %q has to be lowercase alphanumeric with dashes", subset.Name)
+		}
+		key := subset.Service + "/" + subset.Name
+		if seen[key] {
+			return fmt.Errorf("subset %s of %s is declared twice", subset.Name, subset.Service)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+func validateDestination(spec v1beta1.RouteSpec) error {
+	if spec.PrefixRewrite != "" && spec.PathRegex != "" {
+		return fmt.Errorf("prefixRewrite requires prefix or pathExact, not pathRegex")
+	}
+	if spec.RequestTimeout != nil && spec.RequestTimeout.Duration < 0 {
+		return fmt.Errorf("requestTimeout %s is negative", spec.RequestTimeout.Duration)
+	}
+	var retryOnStatusCodes []uint32
+	if spec.RetryOnStatusCodes != nil {
+		retryOnStatusCodes = *spec.RetryOnStatusCodes
+	}
+	for _, code := range retryOnStatusCodes {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("retryOnStatusCodes contains invalid HTTP status %d", code)
+		}
+	}
+	if err := validateHeaderModifiers("requestHeaders", spec.RequestHeaders); err != nil {
+		return err
+	}
+	return validateHeaderModifiers("responseHeaders", spec.ResponseHeaders)
+}
+
+// hopByHopHeaders only apply to a single connection and are managed
+// by the proxy itself, see RFC 7230 section 6.1
+var hopByHopHeaders = map[string]bool{
+	"Connection":          true,
+	"Keep-Alive":          true,
+	"Proxy-Authenticate":  true,
+	"Proxy-Authorization": true,
+	"Proxy-Connection":    true,
+	"Te":                  true,
+	"Trailer":             true,
+	"Transfer-Encoding":   true,
+	"Upgrade":             true,
+}
+
+func validateHeaderModifiers(field string, modifiers *v1beta1.HeaderModifiers) error {
+	if modifiers == nil {
+		return nil
+	}
+
+	var names []string
+	for name := range modifiers.Add {
+		names = append(names, name)
+	}
+	for name := range modifiers.Set {
+		names = append(names, name)
+	}
+	names = append(names, modifiers.Remove...)
+
+	for _, name := range names {
+		if strings.HasPrefix(name, ":") {
+			return fmt.Errorf("%s can't modify pseudo header %s", field, name)
+		}
+		if !httpguts.ValidHeaderFieldName(name) {
+			return fmt.Errorf("%s has an invalid header name %q", field, name)
+		}
+		if hopByHopHeaders[http.CanonicalHeaderKey(name)] {
+			return fmt.Errorf("%s can't modify hop-by-hop header %s", field, name)
+		}
+	}
+	return nil
+}
+
+// validateRegex checks the expression compiles, Envoy uses RE2
+// just like the regexp package
+func validateRegex(field, expr string) error {
+	if expr == "" {
+		return nil
+	}
+	if _, err := regexp.Compile(expr); err != nil {
+		return fmt.Errorf("%s has an invalid regex: %v", field, err)
+	}
+	return nil
+}
+
+// countSet counts the matchers that are set
+func countSet(present bool, values ...string) int {
+	count := 0
+	if present {
+		count++
+	}
+	for _, value := range values {
+		if value != "" {
+			count++
+		}
+	}
+	return count
+}
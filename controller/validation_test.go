@@ -0,0 +1,195 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateRoute(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(spec *v1beta1.RouteSpec)
+		wantErr string
+	}{
+		{
+			name:   "valid prefix route",
+			modify: func(spec *v1beta1.RouteSpec) {},
+		},
+		{
+			name:    "no path",
+			modify:  func(spec *v1beta1.RouteSpec) { spec.Prefix = "" },
+			wantErr: "one of prefix, pathExact or pathRegex is required",
+		},
+		{
+			name:    "several paths",
+			modify:  func(spec *v1beta1.RouteSpec) { spec.PathExact = "/api" },
+			wantErr: "mutually exclusive",
+		},
+		{
+			name:    "relative prefix",
+			modify:  func(spec *v1beta1.RouteSpec) { spec.Prefix = "api" },
+			wantErr: "has to start with /",
+		},
+		{
+			name: "relative exact path",
+			modify: func(spec *v1beta1.RouteSpec) {
+				spec.Prefix = ""
+				spec.PathExact = "health"
+			},
+			wantErr: "has to start with /",
+		},
+		{
+			name: "invalid path regex",
+			modify: func(spec *v1beta1.RouteSpec) {
+				spec.Prefix = ""
+				spec.PathRegex = "/users/[0-9"
+			},
+			wantErr: "pathRegex has an invalid regex",
+		},
+		{
+			name: "header without name",
+			modify: func(spec *v1beta1.RouteSpec) {
+				spec.Headers = []v1beta1.HeaderMatch{{Exact: "1"}}
+			},
+			wantErr: "header match without a name",
+		},
+		{
+			name: "header with two matchers",
+			modify: func(spec *v1beta1.RouteSpec) {
+				spec.Headers = []v1beta1.HeaderMatch{{Name: "x-canary", Present: true, Exact: "1"}}
+			},
+			wantErr: "exactly one of present, exact, prefix, suffix or regex",
+		},
+		{
+			name: "header with invalid regex",
+			modify: func(spec *v1beta1.RouteSpec) {
+				spec.Headers = []v1beta1.HeaderMatch{{Name: "x-canary", Regex: "("}}
+			},
+			wantErr: "header x-canary has an invalid regex",
+		},
+		{
+			name: "inverted header",
+			modify: func(spec *v1beta1.RouteSpec) {
+				spec.Headers = []v1beta1.HeaderMatch{{Name: "x-canary", Present: true, Invert: true}}
+			},
+		},
+		{
+			name: "query parameter without matcher",
+			modify: func(spec *v1beta1.RouteSpec) {
+				spec.QueryParams = []v1beta1.QueryParamMatch{{Name: "debug"}}
+			},
+			wantErr: "exactly one of present, exact or regex",
+		},
+		{
+			name: "unknown method",
+			modify: func(spec *v1beta1.RouteSpec) {
+				spec.Methods = []string{"get"}
+			},
+			wantErr: "unknown HTTP method",
+		},
+		{
+			name: "duplicate method",
+			modify: func(spec *v1beta1.RouteSpec) {
+				spec.Methods = []string{"GET", "GET"}
+			},
+			wantErr: "duplicate HTTP method GET",
+		},
+		{
+			name: "prefix rewrite on regex",
+			modify: func(spec *v1beta1.RouteSpec) {
+				spec.Prefix = ""
+				spec.PathRegex = "/users/.*"
+				spec.PrefixRewrite = "/"
+			},
+			wantErr: "prefixRewrite requires prefix or pathExact",
+		},
+		{
+			name: "negative timeout",
+			modify: func(spec *v1beta1.RouteSpec) {
+				spec.RequestTimeout = &metav1.Duration{Duration: -time.Second}
+			},
+			wantErr: "requestTimeout -1s is negative",
+		},
+		{
+			name: "invalid retry status",
+			modify: func(spec *v1beta1.RouteSpec) {
+				spec.RetryOnStatusCodes = &[]uint32{503, 700}
+			},
+			wantErr: "invalid HTTP status 700",
+		},
+		{
+			name: "header modifiers",
+			modify: func(spec *v1beta1.RouteSpec) {
+				spec.RequestHeaders = &v1beta1.HeaderModifiers{
+					Add:    map[string]string{"x-route": "1"},
+					Set:    map[string]string{"x-env": "prod"},
+					Remove: []string{"x-debug"},
+				}
+			},
+		},
+		{
+			name: "pseudo header",
+			modify: func(spec *v1beta1.RouteSpec) {
+				spec.RequestHeaders = &v1beta1.HeaderModifiers{Set: map[string]string{":authority": "example.com"}}
+			},
+			wantErr: "requestHeaders can't modify pseudo header :authority",
+		},
+		{
+			name: "hop-by-hop header",
+			modify: func(spec *v1beta1.RouteSpec) {
+				spec.ResponseHeaders = &v1beta1.HeaderModifiers{Remove: []string{"connection"}}
+			},
+			wantErr: "responseHeaders can't modify hop-by-hop header connection",
+		},
+		{
+			name: "invalid header name",
+			modify: func(spec *v1beta1.RouteSpec) {
+				spec.RequestHeaders = &v1beta1.HeaderModifiers{Add: map[string]string{"x route": "1"}}
+			},
+			wantErr: "requestHeaders has an invalid header name",
+		},
+		{
+			name: "service and backends",
+			modify: func(spec *v1beta1.RouteSpec) {
+				spec.Backends = []v1beta1.Backend{{Service: "web", Weight: 100}}
+			},
+			wantErr: "service and backends are mutually exclusive",
+		},
+		{
+			name: "backend weights",
+			modify: func(spec *v1beta1.RouteSpec) {
+				spec.Service = ""
+				spec.Backends = []v1beta1.Backend{{Service: "v1", Weight: 60}, {Service: "v2", Weight: 30}}
+			},
+			wantErr: "backend weights add up to 90 instead of 100",
+		},
+		{
+			name: "invalid subset name",
+			modify: func(spec *v1beta1.RouteSpec) {
+				spec.Subsets = []v1beta1.SubsetDefinition{{Service: "web", Name: "V2"}}
+			},
+			wantErr: "subset name \"V2\" has to be lowercase alphanumeric with dashes",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := v1beta1.RouteSpec{Prefix: "/api", Service: "web"}
+			test.modify(&spec)
+
+			err := validateRoute(spec)
+			switch {
+			case test.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case test.wantErr != "" && err == nil:
+				t.Errorf("got no error, want %q", test.wantErr)
+			case test.wantErr != "" && !strings.Contains(err.Error(), test.wantErr):
+				t.Errorf("got error %q, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package v1
+
+import (
+	"github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1beta1"
+)
+
+// ConvertFromV1beta1 converts a v1beta1 route to v1. The v1beta1 path
+// fields are mutually exclusive, if several are set anyway the first of
+// prefix, pathExact and pathRegex is kept.
+func ConvertFromV1beta1(in *v1beta1.Route) *Route {
+	in = in.DeepCopy()
+	out := &Route{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: in.ObjectMeta,
+	}
+	out.APIVersion = SchemeGroupVersion.String()
+
+	spec := in.Spec
+	out.Spec = RouteSpec{
+		Router:                spec.Router,
+		Service:               spec.Service,
+		ServiceSubset:         spec.ServiceSubset,
+		Priority:              spec.Priority,
+		PrefixRewrite:         spec.PrefixRewrite,
+		RequestTimeout:        spec.RequestTimeout,
+		NumRetries:            spec.NumRetries,
+		RetryOnConnectFailure: spec.RetryOnConnectFailure,
+		RetryOnStatusCodes:    spec.RetryOnStatusCodes,
+		RequestHeaders:        (*HeaderModifiers)(spec.RequestHeaders),
+		ResponseHeaders:       (*HeaderModifiers)(spec.ResponseHeaders),
+	}
+
+	switch {
+	case spec.Prefix != "":
+		out.Spec.Match.Path = PathMatch{Type: PathMatchPrefix, Value: spec.Prefix}
+	case spec.PathExact != "":
+		out.Spec.Match.Path = PathMatch{Type: PathMatchExact, Value: spec.PathExact}
+	case spec.PathRegex != "":
+		out.Spec.Match.Path = PathMatch{Type: PathMatchRegex, Value: spec.PathRegex}
+	}
+	for _, header := range spec.Headers {
+		out.Spec.Match.Headers = append(out.Spec.Match.Headers, HeaderMatch(header))
+	}
+	for _, param := range spec.QueryParams {
+		out.Spec.Match.QueryParams = append(out.Spec.Match.QueryParams, QueryParamMatch(param))
+	}
+	for _, method := range spec.Methods {
+		out.Spec.Match.Methods = append(out.Spec.Match.Methods, HTTPMethod(method))
+	}
+	for _, backend := range spec.Backends {
+		out.Spec.Backends = append(out.Spec.Backends, Backend(backend))
+	}
+	for _, subset := range spec.Subsets {
+		out.Spec.Subsets = append(out.Spec.Subsets, SubsetDefinition(subset))
+	}
+
+	out.Status = RouteStatus{
+		ObservedGeneration: in.Status.ObservedGeneration,
+		AppliedIndex:       in.Status.AppliedIndex,
+		Message:            in.Status.Message,
+	}
+	for _, condition := range in.Status.Conditions {
+		out.Status.Conditions = append(out.Status.Conditions, RouteCondition{
+			Type:               RouteConditionType(condition.Type),
+			Status:             condition.Status,
+			LastTransitionTime: condition.LastTransitionTime,
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+		})
+	}
+	return out
+}
+
+// ConvertToV1beta1 converts a v1 route to v1beta1
+func ConvertToV1beta1(in *Route) *v1beta1.Route {
+	in = in.DeepCopy()
+	out := &v1beta1.Route{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: in.ObjectMeta,
+	}
+	out.APIVersion = v1beta1.SchemeGroupVersion.String()
+
+	spec := in.Spec
+	out.Spec = v1beta1.RouteSpec{
+		Router:                spec.Router,
+		Service:               spec.Service,
+		ServiceSubset:         spec.ServiceSubset,
+		Priority:              spec.Priority,
+		PrefixRewrite:         spec.PrefixRewrite,
+		RequestTimeout:        spec.RequestTimeout,
+		NumRetries:            spec.NumRetries,
+		RetryOnConnectFailure: spec.RetryOnConnectFailure,
+		RetryOnStatusCodes:    spec.RetryOnStatusCodes,
+		RequestHeaders:        (*v1beta1.HeaderModifiers)(spec.RequestHeaders),
+		ResponseHeaders:       (*v1beta1.HeaderModifiers)(spec.ResponseHeaders),
+	}
+
+	switch spec.Match.Path.Type {
+	case PathMatchExact:
+		out.Spec.PathExact = spec.Match.Path.Value
+	case PathMatchRegex:
+		out.Spec.PathRegex = spec.Match.Path.Value
+	default:
+		out.Spec.Prefix = spec.Match.Path.Value
+	}
+	for _, header := range spec.Match.Headers {
+		out.Spec.Headers = append(out.Spec.Headers, v1beta1.HeaderMatch(header))
+	}
+	for _, param := range spec.Match.QueryParams {
+		out.Spec.QueryParams = append(out.Spec.QueryParams, v1beta1.QueryParamMatch(param))
+	}
+	for _, method := range spec.Match.Methods {
+		out.Spec.Methods = append(out.Spec.Methods, string(method))
+	}
+	for _, backend := range spec.Backends {
+		out.Spec.Backends = append(out.Spec.Backends, v1beta1.Backend(backend))
+	}
+	for _, subset := range spec.Subsets {
+		out.Spec.Subsets = append(out.Spec.Subsets, v1beta1.SubsetDefinition(subset))
+	}
+
+	out.Status = v1beta1.RouteStatus{
+		ObservedGeneration: in.Status.ObservedGeneration,
+		AppliedIndex:       in.Status.AppliedIndex,
+		Message:            in.Status.Message,
+	}
+	for _, condition := range in.Status.Conditions {
+		out.Status.Conditions = append(out.Status.Conditions, v1beta1.RouteCondition{
+			Type:               v1beta1.RouteConditionType(condition.Type),
+			Status:             condition.Status,
+			LastTransitionTime: condition.LastTransitionTime,
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+		})
+	}
+	return out
+}
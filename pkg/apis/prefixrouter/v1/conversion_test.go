@@ -0,0 +1,136 @@
+package v1
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// v1beta1Route returns a route setting every field, with the path
+// given by setPath
+func v1beta1Route(setPath func(spec *v1beta1.RouteSpec)) *v1beta1.Route {
+	retries := uint32(3)
+	retryOnConnectFailure := false
+	route := &v1beta1.Route{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1beta1.SchemeGroupVersion.String(),
+			Kind:       "Route",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "route1",
+			Namespace:  "default",
+			Labels:     map[string]string{"app": "web"},
+			Finalizers: []string{"prefixrouter.app/consul-route"},
+			Generation: 4,
+		},
+		Spec: v1beta1.RouteSpec{
+			Router:   "router1",
+			Priority: 7,
+			Backends: []v1beta1.Backend{
+				{Service: "web", ServiceSubset: "v1", Weight: 90},
+				{Service: "web", ServiceSubset: "v2", Weight: 10},
+			},
+			Subsets: []v1beta1.SubsetDefinition{
+				{Service: "web", Name: "v2", Filter: "Service.Meta.version == v2", OnlyPassing: true},
+			},
+			Headers: []v1beta1.HeaderMatch{
+				{Name: "x-canary", Exact: "true", Invert: true},
+			},
+			QueryParams: []v1beta1.QueryParamMatch{
+				{Name: "debug", Present: true},
+			},
+			Methods:               []string{"GET", "POST"},
+			PrefixRewrite:         "/",
+			RequestTimeout:        &metav1.Duration{Duration: 5 * time.Second},
+			NumRetries:            &retries,
+			RetryOnConnectFailure: &retryOnConnectFailure,
+			RetryOnStatusCodes:    &[]uint32{502, 503},
+			RequestHeaders: &v1beta1.HeaderModifiers{
+				Add:    map[string]string{"x-route": "route1"},
+				Set:    map[string]string{"x-env": "prod"},
+				Remove: []string{"x-debug"},
+			},
+			ResponseHeaders: &v1beta1.HeaderModifiers{
+				Remove: []string{"server"},
+			},
+		},
+		Status: v1beta1.RouteStatus{
+			ObservedGeneration: 4,
+			AppliedIndex:       42,
+			Message:            "Applied to consul in index 42",
+			Conditions: []v1beta1.RouteCondition{{
+				Type:               v1beta1.RouteReady,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: metav1.Unix(1600000000, 0),
+				Reason:             "Applied",
+			}},
+		},
+	}
+	setPath(&route.Spec)
+	return route
+}
+
+func TestConversionRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		setPath  func(spec *v1beta1.RouteSpec)
+		wantPath PathMatch
+	}{
+		{
+			name:     "prefix",
+			setPath:  func(spec *v1beta1.RouteSpec) { spec.Prefix = "/api" },
+			wantPath: PathMatch{Type: PathMatchPrefix, Value: "/api"},
+		},
+		{
+			name:     "exact",
+			setPath:  func(spec *v1beta1.RouteSpec) { spec.PathExact = "/health" },
+			wantPath: PathMatch{Type: PathMatchExact, Value: "/health"},
+		},
+		{
+			name:     "regex",
+			setPath:  func(spec *v1beta1.RouteSpec) { spec.PathRegex = "/users/[0-9]+" },
+			wantPath: PathMatch{Type: PathMatchRegex, Value: "/users/[0-9]+"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			original := v1beta1Route(test.setPath)
+
+			converted := ConvertFromV1beta1(original)
+			if converted.APIVersion != SchemeGroupVersion.String() {
+				t.Errorf("got apiVersion %s, want %s", converted.APIVersion, SchemeGroupVersion.String())
+			}
+			if converted.Spec.Match.Path != test.wantPath {
+				t.Errorf("got path %+v, want %+v", converted.Spec.Match.Path, test.wantPath)
+			}
+
+			back := ConvertToV1beta1(converted)
+			if !reflect.DeepEqual(back, original) {
+				t.Errorf("v1beta1 round trip changed the route:\ngot  %+v\nwant %+v", back, original)
+			}
+
+			again := ConvertFromV1beta1(back)
+			if !reflect.DeepEqual(again, converted) {
+				t.Errorf("v1 round trip changed the route:\ngot  %+v\nwant %+v", again, converted)
+			}
+		})
+	}
+}
+
+func TestConversionDoesNotShareState(t *testing.T) {
+	original := v1beta1Route(func(spec *v1beta1.RouteSpec) { spec.Prefix = "/api" })
+
+	converted := ConvertFromV1beta1(original)
+	*converted.Spec.NumRetries = 9
+	converted.Spec.RequestHeaders.Add["x-route"] = "changed"
+	converted.Labels["app"] = "changed"
+
+	if *original.Spec.NumRetries != 3 || original.Spec.RequestHeaders.Add["x-route"] != "route1" || original.Labels["app"] != "web" {
+		t.Error("changing the converted route changed the original")
+	}
+}
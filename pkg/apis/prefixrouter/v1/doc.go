@@ -0,0 +1,4 @@
+// +k8s:deepcopy-gen=package
+// +groupName=prefixrouter.app
+
+package v1
@@ -0,0 +1,200 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Route is a specification for a Route resource
+type Route struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RouteSpec   `json:"spec"`
+	Status RouteStatus `json:"status,omitempty"`
+}
+
+// RouteSpec is the spec for a Route resource
+type RouteSpec struct {
+	// Router is the Router the route attaches to, defaults to the
+	// router the controller is started with
+	Router string `json:"router,omitempty"`
+
+	// Match selects the requests the route takes
+	Match RouteMatch `json:"match"`
+
+	// Service receives the traffic, mutually exclusive with Backends
+	Service string `json:"service,omitempty"`
+
+	// ServiceSubset narrows Service down to a subset defined
+	// in its service-resolver
+	ServiceSubset string `json:"serviceSubset,omitempty"`
+
+	// Backends splits the traffic between services by weight
+	Backends []Backend `json:"backends,omitempty"`
+
+	// Subsets declares service subsets prefix router maintains in
+	// the service-resolver entries of the services
+	Subsets []SubsetDefinition `json:"subsets,omitempty"`
+
+	// Priority breaks ties between routes of equal specificity,
+	// higher values are matched first
+	Priority int32 `json:"priority,omitempty"`
+
+	// PrefixRewrite replaces the matched prefix or exact path
+	// before the request is forwarded
+	PrefixRewrite string `json:"prefixRewrite,omitempty"`
+
+	// RequestTimeout is the total time allowed for the request
+	RequestTimeout *metav1.Duration `json:"requestTimeout,omitempty"`
+
+	// NumRetries, RetryOnConnectFailure and RetryOnStatusCodes are
+	// inherited from the Router defaults when unset, set them to 0,
+	// false or an empty list to opt out
+	NumRetries            *uint32   `json:"numRetries,omitempty"`
+	RetryOnConnectFailure *bool     `json:"retryOnConnectFailure,omitempty"`
+	RetryOnStatusCodes    *[]uint32 `json:"retryOnStatusCodes,omitempty"`
+
+	// RequestHeaders modifies headers sent to the service
+	RequestHeaders *HeaderModifiers `json:"requestHeaders,omitempty"`
+
+	// ResponseHeaders modifies headers returned to the client
+	ResponseHeaders *HeaderModifiers `json:"responseHeaders,omitempty"`
+}
+
+// RouteMatch selects requests, all of the criteria have to match
+type RouteMatch struct {
+	Path PathMatch `json:"path"`
+
+	Headers     []HeaderMatch     `json:"headers,omitempty"`
+	QueryParams []QueryParamMatch `json:"queryParams,omitempty"`
+
+	// Methods restricts the route to the listed HTTP methods
+	Methods []HTTPMethod `json:"methods,omitempty"`
+}
+
+// PathMatchType is a valid value for PathMatch.Type
+type PathMatchType string
+
+const (
+	PathMatchPrefix PathMatchType = "Prefix"
+	PathMatchExact  PathMatchType = "Exact"
+	PathMatchRegex  PathMatchType = "Regex"
+)
+
+// PathMatch matches the request path
+type PathMatch struct {
+	// Type defaults to Prefix
+	Type  PathMatchType `json:"type,omitempty"`
+	Value string        `json:"value"`
+}
+
+// HTTPMethod is an HTTP request method
+type HTTPMethod string
+
+// HeaderModifiers changes HTTP headers passing through the route
+type HeaderModifiers struct {
+	// Add appends values, keeping headers that already exist
+	Add map[string]string `json:"add,omitempty"`
+
+	// Set replaces the values of existing headers
+	Set map[string]string `json:"set,omitempty"`
+
+	// Remove drops headers by name
+	Remove []string `json:"remove,omitempty"`
+}
+
+// Backend is a weighted destination of a route
+type Backend struct {
+	Service       string `json:"service"`
+	ServiceSubset string `json:"serviceSubset,omitempty"`
+
+	// Weight is the percentage of requests sent to the backend,
+	// the weights of all backends add up to 100
+	Weight int32 `json:"weight"`
+}
+
+// SubsetDefinition selects the instances of a service forming a subset
+type SubsetDefinition struct {
+	Service string `json:"service"`
+	Name    string `json:"name"`
+
+	// Filter is a Consul filter expression over the service instances,
+	// for example Service.Meta.version == v2
+	Filter string `json:"filter,omitempty"`
+
+	// OnlyPassing excludes instances with warning health checks
+	OnlyPassing bool `json:"onlyPassing,omitempty"`
+}
+
+// HeaderMatch matches a request header. Exactly one of Present,
+// Exact, Prefix, Suffix or Regex has to be set.
+type HeaderMatch struct {
+	Name    string `json:"name"`
+	Present bool   `json:"present,omitempty"`
+	Exact   string `json:"exact,omitempty"`
+	Prefix  string `json:"prefix,omitempty"`
+	Suffix  string `json:"suffix,omitempty"`
+	Regex   string `json:"regex,omitempty"`
+
+	// Invert negates the match
+	Invert bool `json:"invert,omitempty"`
+}
+
+// QueryParamMatch matches a query parameter. Exactly one of Present,
+// Exact or Regex has to be set.
+type QueryParamMatch struct {
+	Name    string `json:"name"`
+	Present bool   `json:"present,omitempty"`
+	Exact   string `json:"exact,omitempty"`
+	Regex   string `json:"regex,omitempty"`
+}
+
+// RouteStatus is the status for a Route resource
+type RouteStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// AppliedIndex is the revision the backend stored the routing
+	// table with when the route was last applied
+	AppliedIndex uint64 `json:"appliedIndex,omitempty"`
+
+	// Message is a human-readable description of the route state
+	Message string `json:"message,omitempty"`
+
+	Conditions []RouteCondition `json:"conditions,omitempty"`
+}
+
+// RouteConditionType is a valid value for RouteCondition.Type
+type RouteConditionType string
+
+const (
+	// RouteReady means the route is synced and not shadowed by another route
+	RouteReady RouteConditionType = "Ready"
+	// RouteSynced means the route was applied to the backend of its router
+	RouteSynced RouteConditionType = "Synced"
+	// RouteConflicted means another route claims the same match
+	RouteConflicted RouteConditionType = "Conflicted"
+)
+
+// RouteCondition describes the state of a route at a certain point
+type RouteCondition struct {
+	Type               RouteConditionType     `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RouteList is a list of Route resources
+type RouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Route `json:"items"`
+}
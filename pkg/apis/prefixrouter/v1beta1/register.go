@@ -27,12 +27,14 @@ func Resource(resource string) schema.GroupResource {
 }
 
 // addKnownTypes adds our types to the API scheme by registering
-// Route and RouteList
+// Route, RouteList, Router and RouterList
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(
 		SchemeGroupVersion,
 		&Route{},
 		&RouteList{},
+		&Router{},
+		&RouterList{},
 	)
 
 	// register the type in the scheme
@@ -1,6 +1,7 @@
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -18,12 +19,155 @@ type Route struct {
 
 // RouteSpec is the spec for a Route resource
 type RouteSpec struct {
-	Prefix  string `json:"prefix"`
+	// Router is the Router the route attaches to, defaults to the
+	// router the controller is started with
+	Router string `json:"router,omitempty"`
+
+	// Exactly one of Prefix, PathExact or PathRegex has to be set
+	Prefix    string `json:"prefix,omitempty"`
+	PathExact string `json:"pathExact,omitempty"`
+	PathRegex string `json:"pathRegex,omitempty"`
+
+	// Service receives the traffic, mutually exclusive with Backends
+	Service string `json:"service,omitempty"`
+
+	// ServiceSubset narrows Service down to a subset defined
+	// in its service-resolver
+	ServiceSubset string `json:"serviceSubset,omitempty"`
+
+	// Backends splits the traffic between services by weight
+	Backends []Backend `json:"backends,omitempty"`
+
+	// Subsets declares service subsets prefix router maintains in
+	// the service-resolver entries of the services
+	Subsets []SubsetDefinition `json:"subsets,omitempty"`
+
+	// Priority breaks ties between routes of equal specificity,
+	// higher values are matched first
+	Priority int32 `json:"priority,omitempty"`
+
+	// Headers all have to match for the route to apply
+	Headers []HeaderMatch `json:"headers,omitempty"`
+
+	// QueryParams all have to match for the route to apply
+	QueryParams []QueryParamMatch `json:"queryParams,omitempty"`
+
+	// Methods restricts the route to the listed HTTP methods
+	Methods []string `json:"methods,omitempty"`
+
+	// PrefixRewrite replaces the matched prefix or exact path
+	// before the request is forwarded
+	PrefixRewrite string `json:"prefixRewrite,omitempty"`
+
+	// RequestTimeout is the total time allowed for the request
+	RequestTimeout *metav1.Duration `json:"requestTimeout,omitempty"`
+
+	// NumRetries, RetryOnConnectFailure and RetryOnStatusCodes are
+	// inherited from the Router defaults when unset, set them to 0,
+	// false or an empty list to opt out
+	NumRetries            *uint32   `json:"numRetries,omitempty"`
+	RetryOnConnectFailure *bool     `json:"retryOnConnectFailure,omitempty"`
+	RetryOnStatusCodes    *[]uint32 `json:"retryOnStatusCodes,omitempty"`
+
+	// RequestHeaders modifies headers sent to the service
+	RequestHeaders *HeaderModifiers `json:"requestHeaders,omitempty"`
+
+	// ResponseHeaders modifies headers returned to the client
+	ResponseHeaders *HeaderModifiers `json:"responseHeaders,omitempty"`
+}
+
+// HeaderModifiers changes HTTP headers passing through the route
+type HeaderModifiers struct {
+	// Add appends values, keeping headers that already exist
+	Add map[string]string `json:"add,omitempty"`
+
+	// Set replaces the values of existing headers
+	Set map[string]string `json:"set,omitempty"`
+
+	// Remove drops headers by name
+	Remove []string `json:"remove,omitempty"`
+}
+
+// Backend is a weighted destination of a route
+type Backend struct {
+	Service       string `json:"service"`
+	ServiceSubset string `json:"serviceSubset,omitempty"`
+
+	// Weight is the percentage of requests sent to the backend,
+	// the weights of all backends add up to 100
+	Weight int32 `json:"weight"`
+}
+
+// SubsetDefinition selects the instances of a service forming a subset
+type SubsetDefinition struct {
 	Service string `json:"service"`
+	Name    string `json:"name"`
+
+	// Filter is a Consul filter expression over the service instances,
+	// for example Service.Meta.version == v2
+	Filter string `json:"filter,omitempty"`
+
+	// OnlyPassing excludes instances with warning health checks
+	OnlyPassing bool `json:"onlyPassing,omitempty"`
+}
+
+// HeaderMatch matches a request header. Exactly one of Present,
+// Exact, Prefix, Suffix or Regex has to be set.
+type HeaderMatch struct {
+	Name    string `json:"name"`
+	Present bool   `json:"present,omitempty"`
+	Exact   string `json:"exact,omitempty"`
+	Prefix  string `json:"prefix,omitempty"`
+	Suffix  string `json:"suffix,omitempty"`
+	Regex   string `json:"regex,omitempty"`
+
+	// Invert negates the match
+	Invert bool `json:"invert,omitempty"`
+}
+
+// QueryParamMatch matches a query parameter. Exactly one of Present,
+// Exact or Regex has to be set.
+type QueryParamMatch struct {
+	Name    string `json:"name"`
+	Present bool   `json:"present,omitempty"`
+	Exact   string `json:"exact,omitempty"`
+	Regex   string `json:"regex,omitempty"`
 }
 
 // RouteStatus is the status for a Route resource
 type RouteStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// AppliedIndex is the revision the backend stored the routing
+	// table with when the route was last applied
+	AppliedIndex uint64 `json:"appliedIndex,omitempty"`
+
+	// Message is a human-readable description of the route state
+	Message string `json:"message,omitempty"`
+
+	Conditions []RouteCondition `json:"conditions,omitempty"`
+}
+
+// RouteConditionType is a valid value for RouteCondition.Type
+type RouteConditionType string
+
+const (
+	// RouteReady means the route is synced and not shadowed by another route
+	RouteReady RouteConditionType = "Ready"
+	// RouteSynced means the route was applied to the backend of its router
+	RouteSynced RouteConditionType = "Synced"
+	// RouteConflicted means another route claims the same match
+	RouteConflicted RouteConditionType = "Conflicted"
+)
+
+// RouteCondition describes the state of a route at a certain point
+type RouteCondition struct {
+	Type               RouteConditionType     `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -35,3 +179,85 @@ type RouteList struct {
 
 	Items []Route `json:"items"`
 }
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Router is a specification for a Router resource, the routing table
+// of a service that Routes attach to
+type Router struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RouterSpec   `json:"spec"`
+	Status RouterStatus `json:"status"`
+}
+
+// RouterSpec is the spec for a Router resource
+type RouterSpec struct {
+	// ServiceName is the service whose requests the router routes,
+	// defaults to the Router name
+	ServiceName string `json:"serviceName,omitempty"`
+
+	// AllowedNamespaces lists the namespaces Routes may attach from,
+	// all namespaces are allowed when empty
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+
+	// ReservedPrefixes are paths no Route may claim. Exact paths are
+	// compared on path segments, prefix and regex Routes are rejected
+	// unless their literal prefix rules out matching a reserved path.
+	ReservedPrefixes []string `json:"reservedPrefixes,omitempty"`
+
+	// Defaults are inherited by Routes that don't set them
+	Defaults RouteDefaults `json:"defaults,omitempty"`
+
+	// DefaultService receives the requests no Route matches
+	DefaultService string `json:"defaultService,omitempty"`
+
+	// Backend is the data plane implementing the routes, defaults
+	// to the backend prefix router is started with
+	Backend string `json:"backend,omitempty"`
+}
+
+// RouteDefaults are destination settings inherited by Routes
+type RouteDefaults struct {
+	RequestTimeout        *metav1.Duration `json:"requestTimeout,omitempty"`
+	NumRetries            uint32           `json:"numRetries,omitempty"`
+	RetryOnConnectFailure bool             `json:"retryOnConnectFailure,omitempty"`
+	RetryOnStatusCodes    []uint32         `json:"retryOnStatusCodes,omitempty"`
+}
+
+// RouterStatus is the status for a Router resource
+type RouterStatus struct {
+	// ObservedGeneration is the most recent generation observed by the controller
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// AppliedIndex is the revision the backend stored the routing
+	// table with after the last successful apply
+	AppliedIndex uint64 `json:"appliedIndex,omitempty"`
+
+	// AttachedRoutes is the number of Routes applied to the router
+	AttachedRoutes int32 `json:"attachedRoutes"`
+
+	// ConflictedRoutes is the number of Routes left out because
+	// an older Route has the same match
+	ConflictedRoutes int32 `json:"conflictedRoutes"`
+
+	// RejectedRoutes is the number of invalid Routes or Routes
+	// violating the router policy
+	RejectedRoutes int32 `json:"rejectedRoutes"`
+
+	// Message is a human-readable description of the router state
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RouterList is a list of Router resources
+type RouterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Router `json:"items"`
+}
@@ -5,6 +5,7 @@ package versioned
 import (
 	"fmt"
 
+	prefixrouterv1 "github.com/oleksiyp/prefixrouter/pkg/client/clientset/versioned/typed/prefixrouter/v1"
 	prefixrouterv1beta1 "github.com/oleksiyp/prefixrouter/pkg/client/clientset/versioned/typed/prefixrouter/v1beta1"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
@@ -14,6 +15,7 @@ import (
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	PrefixrouterV1beta1() prefixrouterv1beta1.PrefixrouterV1beta1Interface
+	PrefixrouterV1() prefixrouterv1.PrefixrouterV1Interface
 }
 
 // Clientset contains the clients for groups. Each group has exactly one
@@ -21,6 +23,7 @@ type Interface interface {
 type Clientset struct {
 	*discovery.DiscoveryClient
 	prefixrouterV1beta1 *prefixrouterv1beta1.PrefixrouterV1beta1Client
+	prefixrouterV1      *prefixrouterv1.PrefixrouterV1Client
 }
 
 // PrefixrouterV1beta1 retrieves the PrefixrouterV1beta1Client
@@ -28,6 +31,11 @@ func (c *Clientset) PrefixrouterV1beta1() prefixrouterv1beta1.PrefixrouterV1beta
 	return c.prefixrouterV1beta1
 }
 
+// PrefixrouterV1 retrieves the PrefixrouterV1Client
+func (c *Clientset) PrefixrouterV1() prefixrouterv1.PrefixrouterV1Interface {
+	return c.prefixrouterV1
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -53,6 +61,10 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 	if err != nil {
 		return nil, err
 	}
+	cs.prefixrouterV1, err = prefixrouterv1.NewForConfig(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfig(&configShallowCopy)
 	if err != nil {
@@ -66,6 +78,7 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 func NewForConfigOrDie(c *rest.Config) *Clientset {
 	var cs Clientset
 	cs.prefixrouterV1beta1 = prefixrouterv1beta1.NewForConfigOrDie(c)
+	cs.prefixrouterV1 = prefixrouterv1.NewForConfigOrDie(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClientForConfigOrDie(c)
 	return &cs
@@ -75,6 +88,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.prefixrouterV1beta1 = prefixrouterv1beta1.New(c)
+	cs.prefixrouterV1 = prefixrouterv1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
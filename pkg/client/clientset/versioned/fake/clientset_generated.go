@@ -4,6 +4,8 @@ package fake
 
 import (
 	clientset "github.com/oleksiyp/prefixrouter/pkg/client/clientset/versioned"
+	prefixrouterv1 "github.com/oleksiyp/prefixrouter/pkg/client/clientset/versioned/typed/prefixrouter/v1"
+	fakeprefixrouterv1 "github.com/oleksiyp/prefixrouter/pkg/client/clientset/versioned/typed/prefixrouter/v1/fake"
 	prefixrouterv1beta1 "github.com/oleksiyp/prefixrouter/pkg/client/clientset/versioned/typed/prefixrouter/v1beta1"
 	fakeprefixrouterv1beta1 "github.com/oleksiyp/prefixrouter/pkg/client/clientset/versioned/typed/prefixrouter/v1beta1/fake"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -64,3 +66,8 @@ var _ clientset.Interface = &Clientset{}
 func (c *Clientset) PrefixrouterV1beta1() prefixrouterv1beta1.PrefixrouterV1beta1Interface {
 	return &fakeprefixrouterv1beta1.FakePrefixrouterV1beta1{Fake: &c.Fake}
 }
+
+// PrefixrouterV1 retrieves the PrefixrouterV1Client
+func (c *Clientset) PrefixrouterV1() prefixrouterv1.PrefixrouterV1Interface {
+	return &fakeprefixrouterv1.FakePrefixrouterV1{Fake: &c.Fake}
+}
@@ -3,6 +3,7 @@
 package fake
 
 import (
+	prefixrouterv1 "github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1"
 	prefixrouterv1beta1 "github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -16,6 +17,7 @@ var codecs = serializer.NewCodecFactory(scheme)
 var parameterCodec = runtime.NewParameterCodec(scheme)
 var localSchemeBuilder = runtime.SchemeBuilder{
 	prefixrouterv1beta1.AddToScheme,
+	prefixrouterv1.AddToScheme,
 }
 
 // AddToScheme adds all types of this clientset into the given scheme. This allows composition
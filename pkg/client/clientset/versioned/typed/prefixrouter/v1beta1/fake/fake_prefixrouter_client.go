@@ -16,6 +16,10 @@ func (c *FakePrefixrouterV1beta1) Routes(namespace string) v1beta1.RouteInterfac
 	return &FakeRoutes{c, namespace}
 }
 
+func (c *FakePrefixrouterV1beta1) Routers() v1beta1.RouterInterface {
+	return &FakeRouters{c}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakePrefixrouterV1beta1) RESTClient() rest.Interface {
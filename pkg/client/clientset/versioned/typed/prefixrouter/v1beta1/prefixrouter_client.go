@@ -11,6 +11,7 @@ import (
 type PrefixrouterV1beta1Interface interface {
 	RESTClient() rest.Interface
 	RoutesGetter
+	RoutersGetter
 }
 
 // PrefixrouterV1beta1Client is used to interact with features provided by the prefixrouter.app group.
@@ -22,6 +23,10 @@ func (c *PrefixrouterV1beta1Client) Routes(namespace string) RouteInterface {
 	return newRoutes(c, namespace)
 }
 
+func (c *PrefixrouterV1beta1Client) Routers() RouterInterface {
+	return newRouters(c)
+}
+
 // NewForConfig creates a new PrefixrouterV1beta1Client for the given config.
 func NewForConfig(c *rest.Config) (*PrefixrouterV1beta1Client, error) {
 	config := *c
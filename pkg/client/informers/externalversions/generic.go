@@ -5,6 +5,7 @@ package externalversions
 import (
 	"fmt"
 
+	v1 "github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1"
 	v1beta1 "github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1beta1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
@@ -36,9 +37,15 @@ func (f *genericInformer) Lister() cache.GenericLister {
 // TODO extend this to unknown resources with a client pool
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
-	// Group=prefixrouter.app, Version=v1beta1
+	// Group=prefixrouter.app, Version=v1
+	case v1.SchemeGroupVersion.WithResource("routes"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Prefixrouter().V1().Routes().Informer()}, nil
+
+		// Group=prefixrouter.app, Version=v1beta1
 	case v1beta1.SchemeGroupVersion.WithResource("routes"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Prefixrouter().V1beta1().Routes().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("routers"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Prefixrouter().V1beta1().Routers().Informer()}, nil
 
 	}
 
@@ -4,6 +4,7 @@ package prefixrouter
 
 import (
 	internalinterfaces "github.com/oleksiyp/prefixrouter/pkg/client/informers/externalversions/internalinterfaces"
+	v1 "github.com/oleksiyp/prefixrouter/pkg/client/informers/externalversions/prefixrouter/v1"
 	v1beta1 "github.com/oleksiyp/prefixrouter/pkg/client/informers/externalversions/prefixrouter/v1beta1"
 )
 
@@ -11,6 +12,8 @@ import (
 type Interface interface {
 	// V1beta1 provides access to shared informers for resources in V1beta1.
 	V1beta1() v1beta1.Interface
+	// V1 provides access to shared informers for resources in V1.
+	V1() v1.Interface
 }
 
 type group struct {
@@ -28,3 +31,8 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (g *group) V1beta1() v1beta1.Interface {
 	return v1beta1.New(g.factory, g.namespace, g.tweakListOptions)
 }
+
+// V1 returns a new v1.Interface.
+func (g *group) V1() v1.Interface {
+	return v1.New(g.factory, g.namespace, g.tweakListOptions)
+}
@@ -10,6 +10,8 @@ import (
 type Interface interface {
 	// Routes returns a RouteInformer.
 	Routes() RouteInformer
+	// Routers returns a RouterInformer.
+	Routers() RouterInformer
 }
 
 type version struct {
@@ -27,3 +29,8 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (v *version) Routes() RouteInformer {
 	return &routeInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// Routers returns a RouterInformer.
+func (v *version) Routers() RouterInformer {
+	return &routerInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
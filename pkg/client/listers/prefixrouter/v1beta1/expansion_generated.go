@@ -9,3 +9,7 @@ type RouteListerExpansion interface{}
 // RouteNamespaceListerExpansion allows custom methods to be added to
 // RouteNamespaceLister.
 type RouteNamespaceListerExpansion interface{}
+
+// RouterListerExpansion allows custom methods to be added to
+// RouterLister.
+type RouterListerExpansion interface{}
@@ -0,0 +1,236 @@
+package consul
+
+import (
+	"encoding/json"
+	"fmt"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/oleksiyp/prefixrouter/pkg/routing"
+	"go.uber.org/zap"
+)
+
+// BackendName is the name routers select the Consul backend by
+const BackendName = "consul"
+
+// Backend routes through the service-router config entry of the router
+// service, with service-splitter entries for weighted routes and
+// service-resolver entries for the declared subsets
+type Backend struct {
+	client *consulapi.Client
+	owner  string
+	logger *zap.SugaredLogger
+}
+
+// NewBackend creates the Consul backend writing through client, tagging
+// what it writes as owned by the prefix router instance owner
+func NewBackend(client *consulapi.Client, owner string, logger *zap.SugaredLogger) *Backend {
+	return &Backend{client: client, owner: owner, logger: logger}
+}
+
+var (
+	_ routing.Backend   = Backend{}
+	_ routing.Watcher   = Backend{}
+	_ routing.Inventory = Backend{}
+	_ routing.Registry  = Backend{}
+	_ routing.Checker   = Backend{}
+)
+
+// Apply writes the table and returns the ModifyIndex of the
+// service-router entry
+func (b Backend) Apply(table routing.Table) (uint64, error) {
+	service := table.Service
+	entries := b.desiredEntries(table)
+
+	// entries the router points to are written before it
+	// and removed only after it stopped pointing to them
+	if err := b.applyEntries(service, entries); err != nil {
+		return 0, err
+	}
+	index, err := b.applyRoutes(service, consulRoutes(service, table.Routes), consulRoutes(service, table.Fallback))
+	if err != nil {
+		return 0, err
+	}
+	if err := b.removeStaleEntries(service, entries); err != nil {
+		return 0, err
+	}
+	return index, nil
+}
+
+func (b Backend) desiredEntries(table routing.Table) []consulapi.ConfigEntry {
+	entries := resolverEntries(table.Service, table.Subsets)
+	entries = append(entries, splitEntries(table.Service, table.Routes)...)
+	entries = append(entries, splitEntries(table.Service, table.Fallback)...)
+	for _, entry := range entries {
+		entry.GetMeta()[ownerMetaKey] = b.owner
+	}
+	return entries
+}
+
+// Current reads the routes prefix router owns in the service-router
+// entry, in order, with the subsets of the resolvers it manages.
+// Fallback routes are returned as part of Routes.
+func (b Backend) Current(service string) (*routing.Table, error) {
+	entry, err := b.getServiceRouter(service)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+
+	splitters := make(map[string]*consulapi.ServiceSplitterConfigEntry)
+	entries, err := b.listEntries(consulapi.ServiceSplitter)
+	if err != nil {
+		return nil, err
+	}
+	for name, entry := range entries {
+		if splitter, ok := entry.(*consulapi.ServiceSplitterConfigEntry); ok && splitter.Meta[managedByMetaKey] == service {
+			splitters[name] = splitter
+		}
+	}
+
+	table := &routing.Table{Service: service, Revision: entry.ModifyIndex}
+	owned := ownedFingerprints(entry.Meta)
+	for _, route := range entry.Routes {
+		if owned[routeFingerprint(route)] {
+			table.Routes = append(table.Routes, tableRoute(route, splitters))
+		}
+	}
+
+	entries, err = b.listEntries(consulapi.ServiceResolver)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		resolver, ok := entry.(*consulapi.ServiceResolverConfigEntry)
+		if !ok || resolver.Meta[managedByMetaKey] != service {
+			continue
+		}
+		for name, subset := range resolver.Subsets {
+			table.Subsets = append(table.Subsets, routing.Subset{
+				Service:     resolver.Name,
+				Name:        name,
+				Filter:      subset.Filter,
+				OnlyPassing: subset.OnlyPassing,
+			})
+		}
+	}
+	table.Subsets, _ = routing.MergeSubsets(table.Subsets)
+	return table, nil
+}
+
+// Diff compares the tables as Consul config entries
+func (b Backend) Diff(desired, current *routing.Table) []string {
+	var want, have []consulapi.ServiceRoute
+	var wantEntries, haveEntries []consulapi.ConfigEntry
+	if desired != nil {
+		want = consulRoutes(desired.Service, append(append([]routing.Route{}, desired.Routes...), desired.Fallback...))
+		wantEntries = b.desiredEntries(*desired)
+	}
+	if current != nil {
+		have = consulRoutes(current.Service, append(append([]routing.Route{}, current.Routes...), current.Fallback...))
+		haveEntries = b.desiredEntries(*current)
+	}
+
+	var diff []string
+	for i, route := range want {
+		switch {
+		case i >= len(have):
+			diff = append(diff, fmt.Sprintf("route %d on %s is missing", i, routePath(route)))
+		case !sameConsulRoute(route, have[i]):
+			diff = append(diff, fmt.Sprintf("route %d on %s was changed", i, routePath(route)))
+		}
+	}
+	for i := len(want); i < len(have); i++ {
+		diff = append(diff, fmt.Sprintf("route %d on %s is unexpected", i, routePath(have[i])))
+	}
+
+	haveByName := make(map[string]consulapi.ConfigEntry, len(haveEntries))
+	for _, entry := range haveEntries {
+		haveByName[entry.GetKind()+"/"+entry.GetName()] = entry
+	}
+	for _, entry := range wantEntries {
+		current, ok := haveByName[entry.GetKind()+"/"+entry.GetName()]
+		switch {
+		case !ok:
+			diff = append(diff, fmt.Sprintf("%s %s is missing", entry.GetKind(), entry.GetName()))
+		case !sameEntry(entry, current):
+			diff = append(diff, fmt.Sprintf("%s %s was changed", entry.GetKind(), entry.GetName()))
+		}
+	}
+	return diff
+}
+
+// Release removes the owned routes from the service-router entry and
+// deletes the entries managed for the service
+func (b Backend) Release(service string) error {
+	if _, err := b.applyRoutes(service, nil, nil); err != nil {
+		return err
+	}
+	return b.removeStaleEntries(service, nil)
+}
+
+// Services returns the services with service-router routes or config
+// entries written by this prefix router instance. Services of other
+// instances sharing the Consul cluster are left out.
+func (b Backend) Services() ([]string, error) {
+	services := map[string]bool{}
+
+	routers, err := b.listEntries(consulapi.ServiceRouter)
+	if err != nil {
+		return nil, err
+	}
+	for name, entry := range routers {
+		meta := entry.GetMeta()
+		if len(ownedFingerprints(meta)) > 0 && meta[ownerMetaKey] == b.owner {
+			services[name] = true
+		}
+	}
+
+	for _, kind := range managedKinds {
+		entries, err := b.listEntries(kind)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			meta := entry.GetMeta()
+			if service := meta[managedByMetaKey]; service != "" && meta[ownerMetaKey] == b.owner {
+				services[service] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(services))
+	for service := range services {
+		names = append(names, service)
+	}
+	return names, nil
+}
+
+// RegisteredServices returns the services in the Consul catalog
+func (b Backend) RegisteredServices() (map[string]bool, error) {
+	catalog, _, err := b.client.Catalog().Services(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	services := make(map[string]bool, len(catalog))
+	for service := range catalog {
+		services[service] = true
+	}
+	return services, nil
+}
+
+func routePath(route consulapi.ServiceRoute) string {
+	if route.Match == nil || route.Match.HTTP == nil {
+		return ""
+	}
+	return routing.Match{
+		PathPrefix: route.Match.HTTP.PathPrefix,
+		PathExact:  route.Match.HTTP.PathExact,
+		PathRegex:  route.Match.HTTP.PathRegex,
+	}.Path()
+}
+
+func sameConsulRoute(a, b consulapi.ServiceRoute) bool {
+	aJSON, _ := json.Marshal(a)
+	bJSON, _ := json.Marshal(b)
+	return string(aJSON) == string(bJSON)
+}
@@ -0,0 +1,170 @@
+package consul
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"go.uber.org/zap"
+)
+
+// Token is an ACL token that can be swapped while requests are running
+type Token struct {
+	mu    sync.RWMutex
+	value string
+}
+
+func (t *Token) Get() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.value
+}
+
+func (t *Token) Set(value string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.value = value
+}
+
+// tokenTransport sets the current token on every request, so a rotated
+// token is used without creating a new client
+type tokenTransport struct {
+	token *Token
+	next  http.RoundTripper
+}
+
+func (t tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token := t.token.Get()
+	if token == "" || req.Header.Get("X-Consul-Token") != "" {
+		return t.next.RoundTrip(req)
+	}
+
+	// round trippers must not modify the request
+	req = req.Clone(req.Context())
+	req.Header.Set("X-Consul-Token", token)
+	return t.next.RoundTrip(req)
+}
+
+// NewClient creates a Consul client authenticating with token.
+// Settings left empty fall back to the CONSUL_* environment variables.
+func NewClient(config Config, token *Token) (*consulapi.Client, error) {
+	if strings.HasPrefix(config.Address, "unix://") {
+		return nil, fmt.Errorf("unix socket addresses are not supported")
+	}
+
+	clientConfig := consulapi.DefaultConfig()
+	if config.Address != "" {
+		clientConfig.Address = config.Address
+	}
+	if config.Scheme != "" {
+		clientConfig.Scheme = config.Scheme
+	}
+	if config.Datacenter != "" {
+		clientConfig.Datacenter = config.Datacenter
+	}
+	if config.Namespace != "" {
+		clientConfig.Namespace = config.Namespace
+	}
+	if config.Partition != "" {
+		clientConfig.Partition = config.Partition
+	}
+	if config.CAFile != "" {
+		clientConfig.TLSConfig.CAFile = config.CAFile
+	}
+	if config.CertFile != "" {
+		clientConfig.TLSConfig.CertFile = config.CertFile
+	}
+	if config.KeyFile != "" {
+		clientConfig.TLSConfig.KeyFile = config.KeyFile
+	}
+
+	// the token is set by the transport, the environment one included
+	switch {
+	case config.TokenFile != "":
+		value, err := readToken(config.TokenFile)
+		if err != nil {
+			return nil, err
+		}
+		token.Set(value)
+	case config.Token != "":
+		token.Set(config.Token)
+	case token.Get() == "" && clientConfig.TokenFile != "":
+		// like the Consul client, the token file wins over the token
+		value, err := readToken(clientConfig.TokenFile)
+		if err != nil {
+			return nil, err
+		}
+		token.Set(value)
+	case token.Get() == "":
+		token.Set(clientConfig.Token)
+	}
+	clientConfig.Token = ""
+	clientConfig.TokenFile = ""
+
+	httpClient, err := consulapi.NewHttpClient(clientConfig.Transport, clientConfig.TLSConfig)
+	if err != nil {
+		return nil, err
+	}
+	httpClient.Transport = tokenTransport{token: token, next: httpClient.Transport}
+	clientConfig.HttpClient = httpClient
+
+	return consulapi.NewClient(clientConfig)
+}
+
+// WatchTokenFile polls the token file and swaps the token when the
+// file content changes, e.g. after the token was rotated
+func WatchTokenFile(path string, token *Token, interval time.Duration, logger *zap.SugaredLogger, stopCh <-chan struct{}) {
+	var lastModified time.Time
+	var lastContent []byte
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			logger.Warnf("Failed to check Consul token file %s: %v", path, err)
+			continue
+		}
+		if info.ModTime().Equal(lastModified) {
+			continue
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			logger.Warnf("Failed to read Consul token file %s: %v", path, err)
+			continue
+		}
+		lastModified = info.ModTime()
+		if bytes.Equal(content, lastContent) {
+			continue
+		}
+		lastContent = content
+
+		value := strings.TrimSpace(string(content))
+		if value == "" || value == token.Get() {
+			continue
+		}
+		token.Set(value)
+		logger.Infof("Consul token reloaded from %s", path)
+	}
+}
+
+func readToken(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %v", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
@@ -0,0 +1,78 @@
+package consul
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"sigs.k8s.io/yaml"
+)
+
+// Config is the Consul client configuration, settable by flags
+// or from a YAML or JSON file
+type Config struct {
+	// Address is the host:port of the Consul HTTP API
+	Address string `json:"address,omitempty"`
+	// Scheme is http or https
+	Scheme string `json:"scheme,omitempty"`
+
+	CAFile   string `json:"caFile,omitempty"`
+	CertFile string `json:"certFile,omitempty"`
+	KeyFile  string `json:"keyFile,omitempty"`
+
+	// Token is the ACL token, ignored when TokenFile is set
+	Token string `json:"token,omitempty"`
+	// TokenFile contains the ACL token, it is read again when it changes
+	TokenFile string `json:"tokenFile,omitempty"`
+
+	Datacenter string `json:"datacenter,omitempty"`
+
+	// Namespace and Partition are available in Consul Enterprise only
+	Namespace string `json:"namespace,omitempty"`
+	Partition string `json:"partition,omitempty"`
+}
+
+// LoadConfigFile reads the configuration from a YAML or JSON file
+func LoadConfigFile(path string) (Config, error) {
+	config := Config{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return config, nil
+}
+
+// Merge returns the configuration with the fields set in overrides
+// replacing its own
+func (c Config) Merge(overrides Config) Config {
+	merge := func(value *string, override string) {
+		if override != "" {
+			*value = override
+		}
+	}
+	merge(&c.Address, overrides.Address)
+	merge(&c.Scheme, overrides.Scheme)
+	merge(&c.CAFile, overrides.CAFile)
+	merge(&c.CertFile, overrides.CertFile)
+	merge(&c.KeyFile, overrides.KeyFile)
+	merge(&c.Token, overrides.Token)
+	merge(&c.TokenFile, overrides.TokenFile)
+	merge(&c.Datacenter, overrides.Datacenter)
+	merge(&c.Namespace, overrides.Namespace)
+	merge(&c.Partition, overrides.Partition)
+	return c
+}
+
+// WithEnvironmentTokenFile returns the configuration with TokenFile
+// taken from CONSUL_HTTP_TOKEN_FILE when no token is configured, so
+// that the file is reloaded the same way as a configured one
+func (c Config) WithEnvironmentTokenFile() Config {
+	if c.Token == "" && c.TokenFile == "" {
+		c.TokenFile = os.Getenv(consulapi.HTTPTokenFileEnvName)
+	}
+	return c
+}
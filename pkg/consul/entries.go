@@ -0,0 +1,165 @@
+package consul
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/oleksiyp/prefixrouter/pkg/routing"
+)
+
+const (
+	// managedByMetaKey marks config entries created by prefix router,
+	// the value is the router service the entry belongs to
+	managedByMetaKey = "prefix-router-managed-by"
+	// routeMetaKey records the route an entry was created for
+	routeMetaKey = "prefix-router-route"
+	// ownerMetaKey names the prefix router instance that wrote the
+	// routes or the entry, only that instance cleans them up
+	ownerMetaKey = "prefix-router-owner"
+)
+
+// managedKinds are the config entry kinds prefix router creates next to
+// the service-router, in the order they have to be written. Entries
+// referencing others come later and are deleted in reverse order.
+var managedKinds = []string{
+	consulapi.ServiceDefaults,
+	consulapi.ServiceResolver,
+	consulapi.ServiceSplitter,
+}
+
+// applyEntries writes the desired entries that differ from Consul,
+// refusing to overwrite entries prefix router doesn't manage
+func (b Backend) applyEntries(service string, desired []consulapi.ConfigEntry) error {
+	for _, kind := range managedKinds {
+		existing, err := b.listEntries(kind)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range desired {
+			if entry.GetKind() != kind {
+				continue
+			}
+
+			current, ok := existing[entry.GetName()]
+			if ok && current.GetMeta()[managedByMetaKey] != service {
+				return fmt.Errorf("%s %s exists and is not managed by router %s", kind, entry.GetName(), service)
+			}
+			if ok && sameEntry(current, entry) {
+				continue
+			}
+
+			written, _, err := b.client.ConfigEntries().Set(entry, nil)
+			recordConsulWrite(service, err)
+			if err != nil {
+				return fmt.Errorf("failed to write %s %s: %v", kind, entry.GetName(), err)
+			}
+			if !written {
+				return fmt.Errorf("failed to write %s %s: HTTP request returned not 'true'", kind, entry.GetName())
+			}
+			b.logger.Infof("Applied %s %s", kind, entry.GetName())
+		}
+	}
+	return nil
+}
+
+// SubsetConflicts finds the services whose service-resolver exists
+// without being managed for the router service, prefix router can't
+// declare subsets of those
+func (b Backend) SubsetConflicts(service string, subsets []routing.Subset) (map[string]error, error) {
+	if len(subsets) == 0 {
+		return nil, nil
+	}
+	resolvers, err := b.listEntries(consulapi.ServiceResolver)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := make(map[string]error)
+	for _, subset := range subsets {
+		resolver, ok := resolvers[subset.Service]
+		if !ok {
+			continue
+		}
+		switch managedBy := resolver.GetMeta()[managedByMetaKey]; managedBy {
+		case service:
+		case "":
+			conflicts[subset.Service] = fmt.Errorf("service-resolver %s exists and is not managed by prefix router", subset.Service)
+		default:
+			conflicts[subset.Service] = fmt.Errorf("service-resolver %s is managed by router %s", subset.Service, managedBy)
+		}
+	}
+	return conflicts, nil
+}
+
+// removeStaleEntries deletes entries managed for the router service that are no
+// longer desired. It runs after the service-router stopped referencing them.
+func (b Backend) removeStaleEntries(service string, desired []consulapi.ConfigEntry) error {
+	keep := make(map[string]bool, len(desired))
+	for _, entry := range desired {
+		keep[entry.GetKind()+"/"+entry.GetName()] = true
+	}
+
+	for i := len(managedKinds) - 1; i >= 0; i-- {
+		kind := managedKinds[i]
+		existing, err := b.listEntries(kind)
+		if err != nil {
+			return err
+		}
+
+		for name, entry := range existing {
+			if entry.GetMeta()[managedByMetaKey] != service || keep[kind+"/"+name] {
+				continue
+			}
+
+			_, err := b.client.ConfigEntries().Delete(kind, name, nil)
+			recordConsulWrite(service, err)
+			if err != nil {
+				return fmt.Errorf("failed to delete %s %s: %v", kind, name, err)
+			}
+			b.logger.Infof("Deleted %s %s", kind, name)
+		}
+	}
+	return nil
+}
+
+func (b Backend) listEntries(kind string) (map[string]consulapi.ConfigEntry, error) {
+	entries, _, err := b.client.ConfigEntries().List(kind, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s entries: %v", kind, err)
+	}
+
+	byName := make(map[string]consulapi.ConfigEntry, len(entries))
+	for _, entry := range entries {
+		byName[entry.GetName()] = entry
+	}
+	return byName, nil
+}
+
+// sameEntry compares entries ignoring the Raft indexes
+func sameEntry(a, b consulapi.ConfigEntry) bool {
+	return reflect.DeepEqual(entryFields(a), entryFields(b))
+}
+
+func entryFields(entry consulapi.ConfigEntry) map[string]interface{} {
+	var fields map[string]interface{}
+	data, _ := json.Marshal(entry)
+	_ = json.Unmarshal(data, &fields)
+	delete(fields, "CreateIndex")
+	delete(fields, "ModifyIndex")
+	return fields
+}
+
+// managedMeta tags an entry as managed for the router service, route is empty
+// for entries shared by several routes
+func managedMeta(serviceName, route string) map[string]string {
+	meta := map[string]string{
+		managedByMetaKey: serviceName,
+	}
+	if route != "" {
+		meta[routeMetaKey] = route
+	}
+	return meta
+}
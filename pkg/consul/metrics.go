@@ -0,0 +1,22 @@
+package consul
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var consulWritesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "prefixrouter_consul_writes_total",
+	Help: "Number of config entry writes to Consul by routed service and result, either success or failure.",
+}, []string{"service", "result"})
+
+func init() {
+	prometheus.MustRegister(consulWritesTotal)
+}
+
+func recordConsulWrite(service string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	consulWritesTotal.WithLabelValues(service, result).Inc()
+}
@@ -0,0 +1,152 @@
+package consul
+
+import (
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/oleksiyp/prefixrouter/pkg/routing"
+)
+
+// consulRoutes converts routes to service-router routes
+func consulRoutes(serviceName string, routes []routing.Route) []consulapi.ServiceRoute {
+	converted := make([]consulapi.ServiceRoute, 0, len(routes))
+	for _, route := range routes {
+		converted = append(converted, consulapi.ServiceRoute{
+			Match:       consulMatch(route.Match),
+			Destination: consulDestination(serviceName, route),
+		})
+	}
+	return converted
+}
+
+func consulMatch(match routing.Match) *consulapi.ServiceRouteMatch {
+	httpMatch := &consulapi.ServiceRouteHTTPMatch{
+		PathExact:  match.PathExact,
+		PathPrefix: match.PathPrefix,
+		PathRegex:  match.PathRegex,
+		Methods:    match.Methods,
+	}
+
+	for _, header := range match.Headers {
+		httpMatch.Header = append(httpMatch.Header, consulapi.ServiceRouteHTTPMatchHeader{
+			Name:    header.Name,
+			Present: header.Present,
+			Exact:   header.Exact,
+			Prefix:  header.Prefix,
+			Suffix:  header.Suffix,
+			Regex:   header.Regex,
+			Invert:  header.Invert,
+		})
+	}
+
+	for _, param := range match.QueryParams {
+		httpMatch.QueryParam = append(httpMatch.QueryParam, consulapi.ServiceRouteHTTPMatchQueryParam{
+			Name:    param.Name,
+			Present: param.Present,
+			Exact:   param.Exact,
+			Regex:   param.Regex,
+		})
+	}
+
+	return &consulapi.ServiceRouteMatch{
+		HTTP: httpMatch,
+	}
+}
+
+// consulDestination points at the only target of the route or the
+// virtual service splitting traffic between its targets
+func consulDestination(serviceName string, route routing.Route) *consulapi.ServiceRouteDestination {
+	destination := route.Destination
+	converted := &consulapi.ServiceRouteDestination{
+		PrefixRewrite:         destination.PrefixRewrite,
+		RequestTimeout:        destination.RequestTimeout,
+		NumRetries:            destination.NumRetries,
+		RetryOnConnectFailure: destination.RetryOnConnectFailure,
+		RetryOnStatusCodes:    destination.RetryOnStatusCodes,
+		RequestHeaders:        consulHeaderModifiers(destination.RequestHeaders),
+		ResponseHeaders:       consulHeaderModifiers(destination.ResponseHeaders),
+	}
+	switch {
+	case len(destination.Targets) == 1:
+		converted.Service = destination.Targets[0].Service
+		converted.ServiceSubset = destination.Targets[0].Subset
+	case len(destination.Targets) > 1:
+		converted.Service = splitService(serviceName, route)
+	}
+	return converted
+}
+
+func consulHeaderModifiers(modifiers *routing.HeaderModifiers) *consulapi.HTTPHeaderModifiers {
+	if modifiers == nil {
+		return nil
+	}
+	return &consulapi.HTTPHeaderModifiers{
+		Add:    modifiers.Add,
+		Set:    modifiers.Set,
+		Remove: modifiers.Remove,
+	}
+}
+
+// tableRoute converts a service-router route back, resolving the
+// virtual services of weighted routes through their splitters
+func tableRoute(route consulapi.ServiceRoute, splitters map[string]*consulapi.ServiceSplitterConfigEntry) routing.Route {
+	converted := routing.Route{}
+	if route.Match != nil && route.Match.HTTP != nil {
+		match := route.Match.HTTP
+		converted.Match = routing.Match{
+			PathPrefix: match.PathPrefix,
+			PathExact:  match.PathExact,
+			PathRegex:  match.PathRegex,
+			Methods:    match.Methods,
+		}
+		for _, header := range match.Header {
+			converted.Match.Headers = append(converted.Match.Headers, routing.HeaderMatch(header))
+		}
+		for _, param := range match.QueryParam {
+			converted.Match.QueryParams = append(converted.Match.QueryParams, routing.QueryParamMatch(param))
+		}
+	}
+
+	destination := route.Destination
+	if destination == nil {
+		return converted
+	}
+	converted.Destination = routing.Destination{
+		PrefixRewrite:         destination.PrefixRewrite,
+		RequestTimeout:        destination.RequestTimeout,
+		NumRetries:            destination.NumRetries,
+		RetryOnConnectFailure: destination.RetryOnConnectFailure,
+		RetryOnStatusCodes:    destination.RetryOnStatusCodes,
+		RequestHeaders:        tableHeaderModifiers(destination.RequestHeaders),
+		ResponseHeaders:       tableHeaderModifiers(destination.ResponseHeaders),
+	}
+
+	splitter, ok := splitters[destination.Service]
+	if !ok {
+		converted.Destination.Targets = []routing.Target{{
+			Service: destination.Service,
+			Subset:  destination.ServiceSubset,
+			Weight:  100,
+		}}
+		return converted
+	}
+
+	converted.Name = splitter.Meta[routeMetaKey]
+	for _, split := range splitter.Splits {
+		converted.Destination.Targets = append(converted.Destination.Targets, routing.Target{
+			Service: split.Service,
+			Subset:  split.ServiceSubset,
+			Weight:  int32(split.Weight),
+		})
+	}
+	return converted
+}
+
+func tableHeaderModifiers(modifiers *consulapi.HTTPHeaderModifiers) *routing.HeaderModifiers {
+	if modifiers == nil {
+		return nil
+	}
+	return &routing.HeaderModifiers{
+		Add:    modifiers.Add,
+		Set:    modifiers.Set,
+		Remove: modifiers.Remove,
+	}
+}
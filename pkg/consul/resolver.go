@@ -0,0 +1,43 @@
+package consul
+
+import (
+	"sort"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/oleksiyp/prefixrouter/pkg/routing"
+)
+
+// resolverEntries groups the subsets of the table into one
+// service-resolver entry per service
+func resolverEntries(serviceName string, subsets []routing.Subset) []consulapi.ConfigEntry {
+	resolvers := make(map[string]*consulapi.ServiceResolverConfigEntry)
+	for _, subset := range subsets {
+		resolver, ok := resolvers[subset.Service]
+		if !ok {
+			resolver = &consulapi.ServiceResolverConfigEntry{
+				Kind:    consulapi.ServiceResolver,
+				Name:    subset.Service,
+				Subsets: make(map[string]consulapi.ServiceResolverSubset),
+				Meta:    managedMeta(serviceName, ""),
+			}
+			resolvers[subset.Service] = resolver
+		}
+
+		resolver.Subsets[subset.Name] = consulapi.ServiceResolverSubset{
+			Filter:      subset.Filter,
+			OnlyPassing: subset.OnlyPassing,
+		}
+	}
+
+	services := make([]string, 0, len(resolvers))
+	for service := range resolvers {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	entries := make([]consulapi.ConfigEntry, 0, len(services))
+	for _, service := range services {
+		entries = append(entries, resolvers[service])
+	}
+	return entries
+}
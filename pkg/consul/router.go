@@ -0,0 +1,230 @@
+package consul
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/oleksiyp/prefixrouter/pkg/routing"
+)
+
+const (
+	// ownedRoutesMetaKey prefixes the config entry Meta keys listing
+	// fingerprints of routes written by prefix router. Consul limits
+	// Meta values to 512 characters, so the list is split in chunks.
+	ownedRoutesMetaKey     = "prefix-router-owned-"
+	fingerprintsPerMetaKey = 50
+
+	// casAttempts bounds read-modify-write retries when another
+	// writer changes the config entry concurrently
+	casAttempts = 5
+)
+
+// applyRoutes replaces the routes owned by prefix router in the
+// service-router config entry and returns the ModifyIndex the entry was
+// stored with. Routes written by anyone else are kept in their order,
+// merged into routes by specificity and before the fallback routes
+// evaluated last. Owned routes whose match is taken by them are left out.
+func (b Backend) applyRoutes(service string, routes, fallback []consulapi.ServiceRoute) (uint64, error) {
+	for attempt := 0; attempt < casAttempts; attempt++ {
+		current, err := b.getServiceRouter(service)
+		if err != nil {
+			return 0, err
+		}
+
+		// the Consul namespace and partition come from the client
+		configEntry := &consulapi.ServiceRouterConfigEntry{
+			Kind: consulapi.ServiceRouter,
+			Name: service,
+		}
+		if current != nil {
+			configEntry.Meta = current.Meta
+			configEntry.ModifyIndex = current.ModifyIndex
+		}
+
+		foreign := foreignRoutes(current)
+		kept, keptFallback := withoutMatches(routes, foreign), withoutMatches(fallback, foreign)
+		if skipped := len(routes) + len(fallback) - len(kept) - len(keptFallback); skipped > 0 {
+			b.logger.Warnf("Leaving out %d routes of %s whose match is taken by routes not written by prefix router", skipped, service)
+		}
+
+		owned := append(append([]consulapi.ServiceRoute{}, kept...), keptFallback...)
+		configEntry.Routes = append(mergeRoutes(kept, foreign), keptFallback...)
+		configEntry.Meta = ownershipMeta(configEntry.Meta, owned)
+		if len(owned) > 0 {
+			configEntry.Meta[ownerMetaKey] = b.owner
+		} else {
+			delete(configEntry.Meta, ownerMetaKey)
+		}
+
+		ok, _, err := b.client.ConfigEntries().CAS(configEntry, configEntry.ModifyIndex, nil)
+		recordConsulWrite(service, err)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			b.logger.Debugf("Config entry %s changed concurrently, retrying", service)
+			continue
+		}
+
+		applied, err := b.getServiceRouter(service)
+		if err != nil {
+			return 0, err
+		}
+		if applied == nil {
+			return 0, errors.New("config entry disappeared after write")
+		}
+		return applied.ModifyIndex, nil
+	}
+
+	return 0, fmt.Errorf("config entry kept changing concurrently, gave up after %d attempts", casAttempts)
+}
+
+// getServiceRouter reads the config entry of the service, returning nil
+// if it doesn't exist yet
+func (b Backend) getServiceRouter(service string) (*consulapi.ServiceRouterConfigEntry, error) {
+	entry, _, err := b.client.ConfigEntries().Get(consulapi.ServiceRouter, service, nil)
+	var statusErr consulapi.StatusError
+	if errors.As(err, &statusErr) && statusErr.Code == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	routerEntry, ok := entry.(*consulapi.ServiceRouterConfigEntry)
+	if !ok {
+		return nil, fmt.Errorf("unexpected config entry type %T", entry)
+	}
+	return routerEntry, nil
+}
+
+// foreignRoutes returns the routes of the current entry that were not
+// written by prefix router
+func foreignRoutes(current *consulapi.ServiceRouterConfigEntry) []consulapi.ServiceRoute {
+	if current == nil {
+		return nil
+	}
+
+	owned := ownedFingerprints(current.Meta)
+	var foreign []consulapi.ServiceRoute
+	for _, route := range current.Routes {
+		if !owned[routeFingerprint(route)] {
+			foreign = append(foreign, route)
+		}
+	}
+	return foreign
+}
+
+// withoutMatches leaves out the routes with the same match as one of taken
+func withoutMatches(routes, taken []consulapi.ServiceRoute) []consulapi.ServiceRoute {
+	matches := make(map[string]bool, len(taken))
+	for _, route := range taken {
+		matches[routeFingerprint(route)] = true
+	}
+
+	var kept []consulapi.ServiceRoute
+	for _, route := range routes {
+		if !matches[routeFingerprint(route)] {
+			kept = append(kept, route)
+		}
+	}
+	return kept
+}
+
+// mergeRoutes places every foreign route before the first owned route
+// that is less specific, so that a foreign /api/admin is not shadowed by
+// an owned /api. Both lists keep their order.
+func mergeRoutes(owned, foreign []consulapi.ServiceRoute) []consulapi.ServiceRoute {
+	merged := make([]consulapi.ServiceRoute, 0, len(owned)+len(foreign))
+	next := 0
+	for _, route := range foreign {
+		for next < len(owned) && !routing.Less(tableRoute(route, nil), tableRoute(owned[next], nil)) {
+			merged = append(merged, owned[next])
+			next++
+		}
+		merged = append(merged, route)
+	}
+	return append(merged, owned[next:]...)
+}
+
+// RouteConflicts finds the routes whose match is taken by a route in the
+// service-router entry that prefix router didn't write
+func (b Backend) RouteConflicts(service string, routes []routing.Route) (map[string]error, error) {
+	current, err := b.getServiceRouter(service)
+	if err != nil || current == nil {
+		return nil, err
+	}
+
+	taken := make(map[string]bool)
+	for _, route := range foreignRoutes(current) {
+		taken[routeFingerprint(route)] = true
+	}
+
+	conflicts := make(map[string]error)
+	for i, route := range consulRoutes(service, routes) {
+		if taken[routeFingerprint(route)] {
+			conflicts[routes[i].Name] = fmt.Errorf("service-router %s has a route with the same match on %s not written by prefix router",
+				service, routePath(route))
+		}
+	}
+	return conflicts, nil
+}
+
+// ownershipMeta replaces the owned route fingerprints in meta,
+// leaving keys set by others untouched
+func ownershipMeta(meta map[string]string, managed []consulapi.ServiceRoute) map[string]string {
+	result := make(map[string]string)
+	for key, value := range meta {
+		if !strings.HasPrefix(key, ownedRoutesMetaKey) {
+			result[key] = value
+		}
+	}
+
+	fingerprints := make([]string, 0, len(managed))
+	for _, route := range managed {
+		fingerprints = append(fingerprints, routeFingerprint(route))
+	}
+	sort.Strings(fingerprints)
+
+	for chunk := 0; chunk*fingerprintsPerMetaKey < len(fingerprints); chunk++ {
+		end := (chunk + 1) * fingerprintsPerMetaKey
+		if end > len(fingerprints) {
+			end = len(fingerprints)
+		}
+		result[ownedRoutesMetaKey+strconv.Itoa(chunk)] = strings.Join(fingerprints[chunk*fingerprintsPerMetaKey:end], ",")
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+func ownedFingerprints(meta map[string]string) map[string]bool {
+	owned := make(map[string]bool)
+	for key, value := range meta {
+		if !strings.HasPrefix(key, ownedRoutesMetaKey) {
+			continue
+		}
+		for _, fingerprint := range strings.Split(value, ",") {
+			owned[fingerprint] = true
+		}
+	}
+	return owned
+}
+
+// routeFingerprint identifies a route by its match criteria,
+// which is what decides the traffic the route takes
+func routeFingerprint(route consulapi.ServiceRoute) string {
+	match, _ := json.Marshal(route.Match)
+	hash := fnv.New32a()
+	_, _ = hash.Write(match)
+	return fmt.Sprintf("%08x", hash.Sum32())
+}
@@ -0,0 +1,206 @@
+package consul
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	consulapi "github.com/hashicorp/consul/api"
+)
+
+func prefixRoute(prefix, service string) consulapi.ServiceRoute {
+	return consulapi.ServiceRoute{
+		Match: &consulapi.ServiceRouteMatch{
+			HTTP: &consulapi.ServiceRouteHTTPMatch{PathPrefix: prefix},
+		},
+		Destination: &consulapi.ServiceRouteDestination{Service: service},
+	}
+}
+
+func TestOwnershipMeta(t *testing.T) {
+	many := make([]consulapi.ServiceRoute, 0, 120)
+	for i := 0; i < 120; i++ {
+		many = append(many, prefixRoute(fmt.Sprintf("/route-%d", i), "svc"))
+	}
+
+	tests := []struct {
+		name     string
+		meta     map[string]string
+		managed  []consulapi.ServiceRoute
+		wantKeys []string
+	}{
+		{
+			name:     "nothing owned",
+			meta:     nil,
+			managed:  nil,
+			wantKeys: nil,
+		},
+		{
+			name:     "foreign keys kept",
+			meta:     map[string]string{"team": "edge"},
+			managed:  many[:1],
+			wantKeys: []string{"prefix-router-owned-0", "team"},
+		},
+		{
+			name:     "stale chunks dropped",
+			meta:     map[string]string{"prefix-router-owned-0": "a", "prefix-router-owned-7": "b"},
+			managed:  many[:2],
+			wantKeys: []string{"prefix-router-owned-0"},
+		},
+		{
+			name:     "chunked",
+			meta:     nil,
+			managed:  many,
+			wantKeys: []string{"prefix-router-owned-0", "prefix-router-owned-1", "prefix-router-owned-2"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			meta := ownershipMeta(test.meta, test.managed)
+
+			var keys []string
+			for key, value := range meta {
+				keys = append(keys, key)
+				if strings.HasPrefix(key, ownedRoutesMetaKey) && len(value) > 512 {
+					t.Errorf("%s is %d characters, Consul allows 512", key, len(value))
+				}
+			}
+			sort.Strings(keys)
+			if !reflect.DeepEqual(keys, test.wantKeys) {
+				t.Errorf("got keys %v, want %v", keys, test.wantKeys)
+			}
+
+			owned := ownedFingerprints(meta)
+			if len(owned) != len(test.managed) {
+				t.Errorf("got %d owned fingerprints, want %d", len(owned), len(test.managed))
+			}
+			for _, route := range test.managed {
+				if !owned[routeFingerprint(route)] {
+					t.Errorf("route on %s is not owned", route.Match.HTTP.PathPrefix)
+				}
+			}
+		})
+	}
+}
+
+func TestForeignRoutes(t *testing.T) {
+	owned := prefixRoute("/owned", "a")
+	foreign := prefixRoute("/foreign", "b")
+	other := prefixRoute("/other", "d")
+
+	tests := []struct {
+		name    string
+		current *consulapi.ServiceRouterConfigEntry
+		want    []consulapi.ServiceRoute
+	}{
+		{
+			name:    "no entry",
+			current: nil,
+			want:    nil,
+		},
+		{
+			name: "previously owned routes are dropped",
+			current: &consulapi.ServiceRouterConfigEntry{
+				Routes: []consulapi.ServiceRoute{foreign, owned, other},
+				Meta:   ownershipMeta(nil, []consulapi.ServiceRoute{owned}),
+			},
+			want: []consulapi.ServiceRoute{foreign, other},
+		},
+		{
+			name: "order of foreign routes is kept",
+			current: &consulapi.ServiceRouterConfigEntry{
+				Routes: []consulapi.ServiceRoute{other, owned, foreign},
+				Meta:   ownershipMeta(map[string]string{"team": "edge"}, []consulapi.ServiceRoute{owned}),
+			},
+			want: []consulapi.ServiceRoute{other, foreign},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := foreignRoutes(test.current)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", routePaths(got), routePaths(test.want))
+			}
+		})
+	}
+}
+
+func TestWithoutMatchesKeepsForeignRoutes(t *testing.T) {
+	foreign := []consulapi.ServiceRoute{prefixRoute("/taken", "foreign")}
+	managed := []consulapi.ServiceRoute{prefixRoute("/taken", "managed"), prefixRoute("/free", "managed")}
+
+	got := withoutMatches(managed, foreign)
+	want := managed[1:]
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", routePaths(got), routePaths(want))
+	}
+}
+
+func TestMergeRoutes(t *testing.T) {
+	owned := []consulapi.ServiceRoute{
+		prefixRoute("/api/v1", "a"),
+		prefixRoute("/api", "a"),
+		prefixRoute("/", "a"),
+	}
+
+	tests := []struct {
+		name    string
+		foreign []consulapi.ServiceRoute
+		want    []string
+	}{
+		{
+			name: "no foreign routes",
+			want: []string{"/api/v1", "/api", "/"},
+		},
+		{
+			name:    "more specific foreign route first",
+			foreign: []consulapi.ServiceRoute{prefixRoute("/api/admin", "b")},
+			want:    []string{"/api/admin", "/api/v1", "/api", "/"},
+		},
+		{
+			name:    "less specific foreign route after",
+			foreign: []consulapi.ServiceRoute{prefixRoute("/web", "b")},
+			want:    []string{"/api/v1", "/api", "/web", "/"},
+		},
+		{
+			name:    "foreign order is kept",
+			foreign: []consulapi.ServiceRoute{prefixRoute("/b", "b"), prefixRoute("/api/admin", "b")},
+			want:    []string{"/api/v1", "/api", "/b", "/api/admin", "/"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged := mergeRoutes(owned, test.foreign)
+			got := make([]string, 0, len(merged))
+			for _, route := range merged {
+				got = append(got, routePath(route))
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRouteFingerprintIgnoresDestination(t *testing.T) {
+	if routeFingerprint(prefixRoute("/a", "one")) != routeFingerprint(prefixRoute("/a", "two")) {
+		t.Error("routes with the same match have different fingerprints")
+	}
+	if routeFingerprint(prefixRoute("/a", "one")) == routeFingerprint(prefixRoute("/b", "one")) {
+		t.Error("routes with different matches have the same fingerprint")
+	}
+}
+
+func routePaths(routes []consulapi.ServiceRoute) []string {
+	paths := make([]string, 0, len(routes))
+	for i, route := range routes {
+		paths = append(paths, strconv.Itoa(i)+":"+routePath(route))
+	}
+	return paths
+}
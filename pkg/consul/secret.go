@@ -0,0 +1,101 @@
+package consul
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// SecretKey references a key of a Kubernetes Secret
+type SecretKey struct {
+	Namespace string
+	Name      string
+	Key       string
+}
+
+// ParseSecretKey parses a namespace/name/key reference
+func ParseSecretKey(value string) (SecretKey, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return SecretKey{}, fmt.Errorf("secret reference %q has to be namespace/name/key", value)
+	}
+	return SecretKey{Namespace: parts[0], Name: parts[1], Key: parts[2]}, nil
+}
+
+func (s SecretKey) String() string {
+	return s.Namespace + "/" + s.Name + "/" + s.Key
+}
+
+// WatchTokenSecret keeps token in sync with the Secret key, returning
+// once the Secret was read for the first time. Requests already sent
+// keep the token they started with.
+func WatchTokenSecret(
+	kubeClient kubernetes.Interface,
+	secret SecretKey,
+	token *Token,
+	logger *zap.SugaredLogger,
+	stopCh <-chan struct{},
+) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(kubeClient, 10*time.Minute,
+		informers.WithNamespace(secret.Namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", secret.Name).String()
+		}),
+	)
+	informer := factory.Core().V1().Secrets().Informer()
+
+	update := func(obj interface{}) {
+		object, ok := obj.(*corev1.Secret)
+		if !ok {
+			logger.Errorf("Event Watch received an invalid object: %#v", obj)
+			return
+		}
+
+		value := strings.TrimSpace(string(object.Data[secret.Key]))
+		if value == "" {
+			logger.Warnf("Secret %s has no Consul token, keeping the current one", secret)
+			return
+		}
+		if value == token.Get() {
+			return
+		}
+		token.Set(value)
+		logger.Infof("Consul token reloaded from secret %s", secret)
+	}
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: update,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			update(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			logger.Warnf("Secret %s was deleted, keeping the current Consul token", secret)
+		},
+	})
+
+	go informer.Run(stopCh)
+	if ok := cache.WaitForNamedCacheSync("consul-token", stopCh, informer.HasSynced); !ok {
+		return fmt.Errorf("failed to wait for secret %s to sync", secret)
+	}
+	obj, exists, err := informer.GetStore().GetByKey(secret.Namespace + "/" + secret.Name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("secret %s/%s not found", secret.Namespace, secret.Name)
+	}
+	value := strings.TrimSpace(string(obj.(*corev1.Secret).Data[secret.Key]))
+	if value == "" {
+		return fmt.Errorf("secret %s/%s has no token in key %s", secret.Namespace, secret.Name, secret.Key)
+	}
+	// the event handler may not have run yet
+	token.Set(value)
+	return nil
+}
@@ -0,0 +1,51 @@
+package consul
+
+import (
+	"strings"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/oleksiyp/prefixrouter/pkg/routing"
+)
+
+// splitService names the virtual service the router sends traffic of
+// a weighted route to, its service-splitter divides it between backends
+func splitService(serviceName string, route routing.Route) string {
+	return serviceName + "--" + strings.Replace(route.Name, "/", "--", 1)
+}
+
+// splitEntries returns the service-defaults and service-splitter
+// entries for the routes with more than one target
+func splitEntries(serviceName string, routes []routing.Route) []consulapi.ConfigEntry {
+	var entries []consulapi.ConfigEntry
+	for _, route := range routes {
+		if len(route.Destination.Targets) < 2 {
+			continue
+		}
+
+		name := splitService(serviceName, route)
+		meta := managedMeta(serviceName, route.Name)
+
+		// splitting requires an HTTP based protocol for the virtual service
+		entries = append(entries, &consulapi.ServiceConfigEntry{
+			Kind:     consulapi.ServiceDefaults,
+			Name:     name,
+			Protocol: "http",
+			Meta:     meta,
+		})
+
+		splitter := &consulapi.ServiceSplitterConfigEntry{
+			Kind: consulapi.ServiceSplitter,
+			Name: name,
+			Meta: meta,
+		}
+		for _, target := range route.Destination.Targets {
+			splitter.Splits = append(splitter.Splits, consulapi.ServiceSplit{
+				Weight:        float32(target.Weight),
+				Service:       target.Service,
+				ServiceSubset: target.Subset,
+			})
+		}
+		entries = append(entries, splitter)
+	}
+	return entries
+}
@@ -0,0 +1,55 @@
+package consul
+
+import (
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+)
+
+const (
+	// watchWaitTime is how long a single blocking query waits for changes
+	watchWaitTime = 5 * time.Minute
+	// watchRetryDelay is the pause after a failed blocking query
+	watchRetryDelay = 5 * time.Second
+)
+
+// Watch runs blocking queries against the service-router entries and
+// calls changed whenever any of them was written, by prefix router
+// or anyone else
+func (b Backend) Watch(changed func(), stopCh <-chan struct{}) {
+	var waitIndex uint64
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+		}
+
+		query := &consulapi.QueryOptions{
+			WaitIndex: waitIndex,
+			WaitTime:  watchWaitTime,
+		}
+		_, meta, err := b.client.ConfigEntries().List(consulapi.ServiceRouter, query)
+		if err != nil {
+			b.logger.Warnf("Failed to watch service-router config entries: %v", err)
+			waitIndex = 0
+			select {
+			case <-time.After(watchRetryDelay):
+			case <-stopCh:
+				return
+			}
+			continue
+		}
+
+		// reset the index if it went backwards, e.g. after a Consul restore
+		if meta.LastIndex < waitIndex {
+			waitIndex = 0
+			continue
+		}
+		if meta.LastIndex == waitIndex {
+			continue
+		}
+		waitIndex = meta.LastIndex
+		changed()
+	}
+}
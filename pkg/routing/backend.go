@@ -0,0 +1,53 @@
+package routing
+
+// Backend applies routing tables to a data plane. Routes configured on
+// the data plane by anyone else are left in place.
+type Backend interface {
+	// Apply makes the data plane route the table service by the table
+	// and returns the revision the configuration was stored with
+	Apply(table Table) (uint64, error)
+
+	// Current reads the table applied to the service by prefix router
+	// as the data plane has it now, nil if there is none
+	Current(service string) (*Table, error)
+
+	// Diff describes how current differs from desired as far as the
+	// data plane is concerned, it is empty when they route the same
+	Diff(desired, current *Table) []string
+
+	// Release removes everything prefix router applied to the service
+	Release(service string) error
+}
+
+// Watcher is implemented by backends that notice changes of the data
+// plane configuration, including changes made outside of prefix router
+type Watcher interface {
+	// Watch calls changed after the configuration changed until
+	// stopCh is closed
+	Watch(changed func(), stopCh <-chan struct{})
+}
+
+// Inventory is implemented by backends that can tell which services
+// prefix router applied anything to
+type Inventory interface {
+	Services() ([]string, error)
+}
+
+// Registry is implemented by backends that know which services
+// requests can be sent to
+type Registry interface {
+	RegisteredServices() (map[string]bool, error)
+}
+
+// Checker is implemented by backends sharing the data plane
+// configuration with others. It tells which parts of a table can't be
+// applied without overwriting configuration prefix router doesn't own.
+type Checker interface {
+	// SubsetConflicts returns why subsets of a service can't be declared
+	// for the service the table routes, keyed by the subset service
+	SubsetConflicts(service string, subsets []Subset) (map[string]error, error)
+
+	// RouteConflicts returns why routes can't be applied to the service
+	// because others configured the same match, keyed by route name
+	RouteConflicts(service string, routes []Route) (map[string]error, error)
+}
@@ -0,0 +1,212 @@
+package routing
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// Table is the desired routing of a router service, independent of the
+// data plane implementing it
+type Table struct {
+	// Service is the service whose requests the table routes
+	Service string
+
+	// Routes in evaluation order. Backends place routes not written by
+	// prefix router before the first of them that is less specific.
+	Routes []Route
+
+	// Fallback routes are evaluated after every other route
+	Fallback []Route
+
+	// Subsets declared by the routes, unique by service and name
+	Subsets []Subset
+
+	// Revision the data plane stored the table with, only set on
+	// tables read by Backend.Current
+	Revision uint64
+}
+
+// Route sends the requests matching Match to Destination
+type Route struct {
+	// Name identifies the Route object, namespace/name, empty for
+	// routes prefix router generates itself
+	Name string
+
+	Match       Match
+	Destination Destination
+
+	// Priority breaks ties between routes of equal specificity
+	Priority int32
+}
+
+// Match selects requests, all of the criteria have to match. At most
+// one of PathPrefix, PathExact or PathRegex is set.
+type Match struct {
+	PathPrefix  string            `json:",omitempty"`
+	PathExact   string            `json:",omitempty"`
+	PathRegex   string            `json:",omitempty"`
+	Headers     []HeaderMatch     `json:",omitempty"`
+	QueryParams []QueryParamMatch `json:",omitempty"`
+	Methods     []string          `json:",omitempty"`
+}
+
+// HeaderMatch matches a request header by exactly one of its criteria
+type HeaderMatch struct {
+	Name    string
+	Present bool   `json:",omitempty"`
+	Exact   string `json:",omitempty"`
+	Prefix  string `json:",omitempty"`
+	Suffix  string `json:",omitempty"`
+	Regex   string `json:",omitempty"`
+	Invert  bool   `json:",omitempty"`
+}
+
+// QueryParamMatch matches a query parameter by exactly one of its criteria
+type QueryParamMatch struct {
+	Name    string
+	Present bool   `json:",omitempty"`
+	Exact   string `json:",omitempty"`
+	Regex   string `json:",omitempty"`
+}
+
+// Destination is where a route forwards requests and how
+type Destination struct {
+	// Targets receive the requests split by weight, a single
+	// target takes all of them
+	Targets []Target
+
+	PrefixRewrite         string
+	RequestTimeout        time.Duration
+	NumRetries            uint32
+	RetryOnConnectFailure bool
+	RetryOnStatusCodes    []uint32
+
+	RequestHeaders  *HeaderModifiers
+	ResponseHeaders *HeaderModifiers
+}
+
+// Target is a weighted service or service subset
+type Target struct {
+	Service string
+	Subset  string
+	Weight  int32
+}
+
+// HeaderModifiers changes HTTP headers passing through a route
+type HeaderModifiers struct {
+	Add    map[string]string
+	Set    map[string]string
+	Remove []string
+}
+
+// Subset is a named group of service instances
+type Subset struct {
+	Service     string
+	Name        string
+	Filter      string
+	OnlyPassing bool
+
+	// DeclaredBy is the name of the route declaring the subset
+	DeclaredBy string
+}
+
+// Key identifies the traffic a match takes, routes with equal
+// keys conflict with each other
+func (m Match) Key() string {
+	key, _ := json.Marshal(m)
+	return string(key)
+}
+
+// Path returns whichever path the match is on
+func (m Match) Path() string {
+	switch {
+	case m.PathExact != "":
+		return m.PathExact
+	case m.PathRegex != "":
+		return m.PathRegex
+	default:
+		return m.PathPrefix
+	}
+}
+
+// rank orders path match kinds, lower ranks are evaluated first
+func (m Match) rank() int {
+	switch {
+	case m.PathExact != "":
+		return 0
+	case m.PathRegex != "":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// criteria counts the match conditions besides the path
+func (m Match) criteria() int {
+	count := len(m.Headers) + len(m.QueryParams)
+	if len(m.Methods) > 0 {
+		count++
+	}
+	return count
+}
+
+// Sort puts routes in evaluation order: exact paths first, then regular
+// expressions, as they are written for specific requests and would
+// otherwise be shadowed by catch-all prefixes, then prefixes. Within a
+// group longer paths go first, then routes with more header, query
+// parameter and method criteria, then higher priority, then paths
+// lexically so that the applied configuration is stable across runs.
+func Sort(routes []Route) {
+	sort.Slice(routes, func(i, j int) bool {
+		return Less(routes[i], routes[j])
+	})
+}
+
+// Less reports whether a is evaluated before b in the order of Sort
+func Less(a, b Route) bool {
+	if a.Match.rank() != b.Match.rank() {
+		return a.Match.rank() < b.Match.rank()
+	}
+	aPath, bPath := a.Match.Path(), b.Match.Path()
+	if len(aPath) != len(bPath) {
+		return len(aPath) > len(bPath)
+	}
+	if a.Match.criteria() != b.Match.criteria() {
+		return a.Match.criteria() > b.Match.criteria()
+	}
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	if aPath != bPath {
+		return aPath < bPath
+	}
+	return a.Match.Key() < b.Match.Key()
+}
+
+// MergeSubsets keeps the first declaration of every subset, returning
+// the merged subsets sorted by service and name, and the declarations
+// that defined an already declared subset differently
+func MergeSubsets(declared []Subset) ([]Subset, []Subset) {
+	byKey := make(map[string]Subset)
+	var merged, redefined []Subset
+	for _, subset := range declared {
+		key := subset.Service + "/" + subset.Name
+		if existing, ok := byKey[key]; ok {
+			if existing.Filter != subset.Filter || existing.OnlyPassing != subset.OnlyPassing {
+				redefined = append(redefined, subset)
+			}
+			continue
+		}
+		byKey[key] = subset
+		merged = append(merged, subset)
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		if merged[i].Service != merged[j].Service {
+			return merged[i].Service < merged[j].Service
+		}
+		return merged[i].Name < merged[j].Name
+	})
+	return merged, redefined
+}
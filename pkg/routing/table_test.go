@@ -0,0 +1,127 @@
+package routing
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSort(t *testing.T) {
+	routes := []Route{
+		{Name: "catch-all", Match: Match{PathPrefix: "/"}},
+		{Name: "api", Match: Match{PathPrefix: "/api"}},
+		{Name: "api-v1", Match: Match{PathPrefix: "/api/v1"}},
+		{Name: "api-v2", Match: Match{PathPrefix: "/api/v2"}},
+		{Name: "api-get", Match: Match{PathPrefix: "/api", Methods: []string{"GET"}}},
+		{Name: "api-canary", Match: Match{PathPrefix: "/api", Headers: []HeaderMatch{{Name: "x-canary", Present: true}}}},
+		{Name: "api-priority", Match: Match{PathPrefix: "/api", QueryParams: []QueryParamMatch{{Name: "debug", Present: true}}}, Priority: 10},
+		{Name: "health", Match: Match{PathExact: "/health"}},
+		{Name: "users", Match: Match{PathRegex: "/users/[0-9]+"}},
+	}
+	want := []string{
+		"health",
+		"users",
+		"api-v1",
+		"api-v2",
+		"api-priority",
+		"api-canary",
+		"api-get",
+		"api",
+		"catch-all",
+	}
+
+	// every rotation of the input has to produce the same order
+	for shift := range routes {
+		input := append(append([]Route{}, routes[shift:]...), routes[:shift]...)
+		Sort(input)
+
+		got := make([]string, 0, len(input))
+		for _, route := range input {
+			got = append(got, route.Name)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("rotation %d: got %v, want %v", shift, got, want)
+		}
+	}
+}
+
+func TestSortTieBreakers(t *testing.T) {
+	tests := []struct {
+		name   string
+		first  Route
+		second Route
+	}{
+		{
+			name:   "exact before regex",
+			first:  Route{Match: Match{PathExact: "/a"}},
+			second: Route{Match: Match{PathRegex: "/a/.*/long"}},
+		},
+		{
+			name:   "regex before prefix",
+			first:  Route{Match: Match{PathRegex: "/a"}},
+			second: Route{Match: Match{PathPrefix: "/a/much/longer"}},
+		},
+		{
+			name:   "longer prefix first",
+			first:  Route{Match: Match{PathPrefix: "/ab"}},
+			second: Route{Match: Match{PathPrefix: "/a"}},
+		},
+		{
+			name:   "more criteria first",
+			first:  Route{Match: Match{PathPrefix: "/a", Methods: []string{"GET"}}},
+			second: Route{Match: Match{PathPrefix: "/a"}, Priority: 100},
+		},
+		{
+			name:   "higher priority first",
+			first:  Route{Match: Match{PathPrefix: "/a", Methods: []string{"GET"}}, Priority: 1},
+			second: Route{Match: Match{PathPrefix: "/a", Methods: []string{"PUT"}}},
+		},
+		{
+			name:   "lexical path order",
+			first:  Route{Match: Match{PathPrefix: "/a"}},
+			second: Route{Match: Match{PathPrefix: "/b"}},
+		},
+		{
+			name:   "match key order",
+			first:  Route{Match: Match{PathPrefix: "/a", Methods: []string{"GET"}}},
+			second: Route{Match: Match{PathPrefix: "/a", Methods: []string{"PUT"}}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			routes := []Route{test.second, test.first}
+			Sort(routes)
+			if !reflect.DeepEqual(routes[0], test.first) {
+				t.Errorf("got %+v first, want %+v", routes[0], test.first)
+			}
+		})
+	}
+}
+
+func TestMergeSubsets(t *testing.T) {
+	declared := []Subset{
+		{Service: "web", Name: "v2", Filter: "Service.Meta.version == v2", DeclaredBy: "default/old"},
+		{Service: "api", Name: "v1", Filter: "Service.Meta.version == v1", DeclaredBy: "default/old"},
+		{Service: "web", Name: "v2", Filter: "Service.Meta.version == v2", DeclaredBy: "default/same"},
+		{Service: "web", Name: "v2", Filter: "Service.Meta.version == 2", DeclaredBy: "default/new"},
+		{Service: "web", Name: "v1", OnlyPassing: true, DeclaredBy: "default/new"},
+	}
+
+	merged, redefined := MergeSubsets(declared)
+
+	wantMerged := []Subset{
+		{Service: "api", Name: "v1", Filter: "Service.Meta.version == v1", DeclaredBy: "default/old"},
+		{Service: "web", Name: "v1", OnlyPassing: true, DeclaredBy: "default/new"},
+		{Service: "web", Name: "v2", Filter: "Service.Meta.version == v2", DeclaredBy: "default/old"},
+	}
+	if !reflect.DeepEqual(merged, wantMerged) {
+		t.Errorf("merged: got %+v, want %+v", merged, wantMerged)
+	}
+
+	wantRedefined := []Subset{
+		{Service: "web", Name: "v2", Filter: "Service.Meta.version == 2", DeclaredBy: "default/new"},
+	}
+	if !reflect.DeepEqual(redefined, wantRedefined) {
+		t.Errorf("redefined: got %+v, want %+v", redefined, wantRedefined)
+	}
+}
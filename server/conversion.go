@@ -0,0 +1,87 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	v1 "github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1"
+	"github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1beta1"
+	"go.uber.org/zap"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// serveConvert answers the CRD conversion requests of the API server,
+// translating routes between v1beta1 and v1
+func serveConvert(w http.ResponseWriter, r *http.Request, logger *zap.SugaredLogger) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	review := apiextensionsv1.ConversionReview{}
+	if err := json.Unmarshal(body, &review); err != nil || review.Request == nil {
+		http.Error(w, "malformed conversion review", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = &apiextensionsv1.ConversionResponse{
+		UID:    review.Request.UID,
+		Result: metav1.Status{Status: metav1.StatusSuccess},
+	}
+	for _, object := range review.Request.Objects {
+		converted, err := convertRoute(object.Raw, review.Request.DesiredAPIVersion)
+		if err != nil {
+			logger.Warnf("Failed to convert route to %s: %v", review.Request.DesiredAPIVersion, err)
+			review.Response.ConvertedObjects = nil
+			review.Response.Result = metav1.Status{
+				Status:  metav1.StatusFailure,
+				Message: err.Error(),
+			}
+			break
+		}
+		review.Response.ConvertedObjects = append(review.Response.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(review)
+}
+
+func convertRoute(raw []byte, desiredAPIVersion string) ([]byte, error) {
+	typeMeta := metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, &typeMeta); err != nil {
+		return nil, err
+	}
+	if typeMeta.Kind != "Route" {
+		return nil, fmt.Errorf("unexpected kind %s", typeMeta.Kind)
+	}
+	if typeMeta.APIVersion == desiredAPIVersion {
+		return raw, nil
+	}
+
+	switch {
+	case typeMeta.APIVersion == v1beta1.SchemeGroupVersion.String() && desiredAPIVersion == v1.SchemeGroupVersion.String():
+		route := &v1beta1.Route{}
+		if err := json.Unmarshal(raw, route); err != nil {
+			return nil, err
+		}
+		return json.Marshal(v1.ConvertFromV1beta1(route))
+	case typeMeta.APIVersion == v1.SchemeGroupVersion.String() && desiredAPIVersion == v1beta1.SchemeGroupVersion.String():
+		route := &v1.Route{}
+		if err := json.Unmarshal(raw, route); err != nil {
+			return nil, err
+		}
+		return json.Marshal(v1.ConvertToV1beta1(route))
+	}
+	return nil, fmt.Errorf("conversion from %s to %s is not supported", typeMeta.APIVersion, desiredAPIVersion)
+}
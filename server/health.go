@@ -0,0 +1,89 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// Health aggregates the readiness checks of the controller dependencies
+// and the leader election state served on /readyz
+type Health struct {
+	mu     sync.RWMutex
+	checks []healthCheck
+	leader func() bool
+}
+
+type healthCheck struct {
+	name  string
+	check func() error
+}
+
+type checkResult struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+type readyResponse struct {
+	Ready  bool                   `json:"ready"`
+	Leader bool                   `json:"leader"`
+	Checks map[string]checkResult `json:"checks"`
+}
+
+func NewHealth() *Health {
+	return &Health{}
+}
+
+// AddCheck registers a check that fails readiness when it returns an error
+func (h *Health) AddCheck(name string, check func() error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks = append(h.checks, healthCheck{name, check})
+}
+
+// SetLeader registers the function reporting whether this replica
+// is the leader. Leadership is informational and doesn't affect readiness.
+func (h *Health) SetLeader(leader func() bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.leader = leader
+}
+
+func (h *Health) IsLeader() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.leader != nil && h.leader()
+}
+
+func (h *Health) ready() readyResponse {
+	h.mu.RLock()
+	checks := h.checks
+	h.mu.RUnlock()
+
+	response := readyResponse{
+		Ready:  true,
+		Leader: h.IsLeader(),
+		Checks: make(map[string]checkResult, len(checks)),
+	}
+	for _, c := range checks {
+		result := checkResult{OK: true}
+		if err := c.check(); err != nil {
+			result = checkResult{OK: false, Error: err.Error()}
+			response.Ready = false
+		}
+		response.Checks[c.name] = result
+	}
+	return response
+}
+
+func (h *Health) serveReady(w http.ResponseWriter, r *http.Request) {
+	response := h.ready()
+
+	w.Header().Set("Content-Type", "application/json")
+	if response.Ready {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
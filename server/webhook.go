@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/oleksiyp/prefixrouter/pkg/apis/prefixrouter/v1beta1"
+	"go.uber.org/zap"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RouteValidator decides whether a route may be created or updated
+type RouteValidator func(route *v1beta1.Route) error
+
+// ListenAndServeWebhook serves the validating admission webhook for
+// routes on /validate-route and the CRD conversion webhook on /convert
+// over TLS
+func ListenAndServeWebhook(
+	port string,
+	certFile string,
+	keyFile string,
+	timeout time.Duration,
+	validate RouteValidator,
+	logger *zap.SugaredLogger,
+	stopCh <-chan struct{},
+) {
+	certs, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		logger.Fatalf("Error loading webhook certificate: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/validate-route", func(w http.ResponseWriter, r *http.Request) {
+		serveValidateRoute(w, r, validate, logger)
+	})
+	mux.HandleFunc("/convert", func(w http.ResponseWriter, r *http.Request) {
+		serveConvert(w, r, logger)
+	})
+
+	srv := &http.Server{
+		Addr:         ":" + port,
+		Handler:      mux,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  15 * time.Second,
+		TLSConfig: &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: certs.GetCertificate,
+		},
+	}
+
+	logger.Infof("Starting webhook server on port %s", port)
+
+	// run server in background
+	go func() {
+		if err := srv.ListenAndServeTLS("", ""); err != http.ErrServerClosed {
+			logger.Fatalf("Webhook server crashed %v", err)
+		}
+	}()
+
+	// wait for SIGTERM or SIGINT
+	<-stopCh
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Errorf("Webhook server graceful shutdown failed %v", err)
+	} else {
+		logger.Info("Webhook server stopped")
+	}
+}
+
+func serveValidateRoute(w http.ResponseWriter, r *http.Request, validate RouteValidator, logger *zap.SugaredLogger) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	review := admissionv1beta1.AdmissionReview{}
+	if err := json.Unmarshal(body, &review); err != nil || review.Request == nil {
+		http.Error(w, "malformed admission review", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = admitRoute(review.Request, validate)
+	review.Response.UID = review.Request.UID
+	if !review.Response.Allowed {
+		logger.Infof("Denied route %s/%s: %s", review.Request.Namespace, review.Request.Name, review.Response.Result.Message)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(review)
+}
+
+func admitRoute(request *admissionv1beta1.AdmissionRequest, validate RouteValidator) *admissionv1beta1.AdmissionResponse {
+	if request.Operation == admissionv1beta1.Delete {
+		return &admissionv1beta1.AdmissionResponse{Allowed: true}
+	}
+
+	route := &v1beta1.Route{}
+	if err := json.Unmarshal(request.Object.Raw, route); err != nil {
+		return denied(fmt.Sprintf("failed to decode route: %v", err))
+	}
+	// objects created without metadata.namespace get it from the request
+	if route.Namespace == "" {
+		route.Namespace = request.Namespace
+	}
+
+	// metadata-only updates such as finalizer changes and updates of
+	// routes being deleted must go through, otherwise a route that
+	// fails policy now could neither gain nor lose its finalizer
+	if route.DeletionTimestamp != nil {
+		return &admissionv1beta1.AdmissionResponse{Allowed: true}
+	}
+	if request.Operation == admissionv1beta1.Update {
+		old := &v1beta1.Route{}
+		if err := json.Unmarshal(request.OldObject.Raw, old); err != nil {
+			return denied(fmt.Sprintf("failed to decode route: %v", err))
+		}
+		if equality.Semantic.DeepEqual(old.Spec, route.Spec) {
+			return &admissionv1beta1.AdmissionResponse{Allowed: true}
+		}
+	}
+
+	if err := validate(route); err != nil {
+		return denied(err.Error())
+	}
+	return &admissionv1beta1.AdmissionResponse{Allowed: true}
+}
+
+func denied(message string) *admissionv1beta1.AdmissionResponse {
+	return &admissionv1beta1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Reason:  metav1.StatusReasonInvalid,
+			Message: message,
+			Code:    http.StatusUnprocessableEntity,
+		},
+	}
+}